@@ -0,0 +1,41 @@
+package error_group
+
+import "time"
+
+// Clock abstracts the passage of time for the delays between retries, the deadlines of
+// GoWithDeadline and GoWithTimeout and the task durations reported to observers so that they can
+// be tested deterministically. The default clock uses the time package.
+type Clock interface {
+	After(d time.Duration) <-chan time.Time
+	Now() time.Time
+}
+
+// UseClock makes a group measure and wait for time with clock instead of the time package.
+func UseClock(clock Clock) Option {
+	return func(cfg *config) {
+		cfg.clock = clock
+	}
+}
+
+// clockOrDefault returns the clock configured with UseClock, or the clock backed by the time
+// package if there is none.
+func (cfg config) clockOrDefault() Clock {
+	if cfg.clock == nil {
+		return realClock{}
+	}
+
+	return cfg.clock
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+// After fulfills the Clock interface.
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Now fulfills the Clock interface.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
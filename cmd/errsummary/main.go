@@ -0,0 +1,99 @@
+// Command errsummary summarizes the error groups and error status groups found in logs. It reads
+// the text written by the Error method of error status groups, or their JSON serialization, from
+// the named files or from standard input, and prints the most frequent error messages and the
+// distribution of statuses:
+//
+//	errsummary [-format auto|text|json] [-top 10] [-normalize] [file ...]
+//
+// With -diff it compares two runs instead, reporting how the counts of each message and status
+// changed between them:
+//
+//	errsummary -diff [-format auto|text|json] [-top 10] [-normalize] before.log after.log
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "errsummary:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command with the given arguments.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("errsummary", flag.ContinueOnError)
+	diff := flags.Bool("diff", false, "compare two runs given as two files")
+	format := flags.String("format", formatAuto, "input format: auto, text or json")
+	normalize := flags.Bool("normalize", false, "replace numbers in messages with N before counting them")
+	top := flags.Int("top", 10, "number of messages to list, or 0 for all of them")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *diff {
+		if flags.NArg() != 2 {
+			return errors.New("-diff requires exactly two files")
+		}
+
+		before, err := load(flags.Arg(0), stdin, *format)
+		if err != nil {
+			return err
+		}
+
+		after, err := load(flags.Arg(1), stdin, *format)
+		if err != nil {
+			return err
+		}
+
+		writeDiff(stdout, summarize(before, *normalize), summarize(after, *normalize), *top)
+
+		return nil
+	}
+
+	names := flags.Args()
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+
+	var groups []group
+	for _, name := range names {
+		loaded, err := load(name, stdin, *format)
+		if err != nil {
+			return err
+		}
+
+		groups = append(groups, loaded...)
+	}
+
+	summarize(groups, *normalize).write(stdout, *top)
+
+	return nil
+}
+
+// load parses the groups logged to the file called name, or to stdin if name is "-".
+func load(name string, stdin io.Reader, format string) ([]group, error) {
+	if name == "-" {
+		return parse(stdin, format)
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	groups, err := parse(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The formats accepted by parse.
+const (
+	formatAuto = "auto"
+	formatJSON = "json"
+	formatText = "text"
+)
+
+// Markers of the lines written by the Error method of an error status group, by a
+// ThresholdExceededError and by a FatalError.
+const (
+	highestMarker   = "highest status: ["
+	lowestMarker    = "lowest status: ["
+	countsMarker    = "status counts: ["
+	classesMarker   = "status classes: ["
+	thresholdMarker = "threshold exceeded: "
+	fatalMarker     = "fatal error: "
+)
+
+// maxLineLength is the longest log line parse accepts.
+const maxLineLength = 1024 * 1024
+
+// newRecord matches the start of a log record, which ends the messages of the group before it.
+var newRecord = regexp.MustCompile(`^\d{4}[-/]\d{2}[-/]\d{2}`)
+
+// group is an error group or error status group reconstructed from a log.
+type group struct {
+	hasStatus bool
+	highest   int
+	lowest    int
+	messages  []string
+	statuses  map[int]int
+	tripped   string
+	warnings  []string
+}
+
+// groupJSON is the serialized form of an error group or error status group snapshot.
+type groupJSON struct {
+	Errors            []string    `json:"errors"`
+	ErrorCount        *int        `json:"error_count"`
+	Groups            []groupJSON `json:"groups"`
+	HighestStatus     *int        `json:"highest_status"`
+	LowestStatus      *int        `json:"lowest_status"`
+	Name              string      `json:"name"`
+	Statuses          []int       `json:"statuses"`
+	ThresholdExceeded string      `json:"threshold_exceeded"`
+	Warnings          []string    `json:"warnings"`
+}
+
+// parse reads every group logged to r in the given format. The text format is the output of the
+// Error method of error status groups, optionally preceded by the "threshold exceeded" line of a
+// ThresholdExceededError or the "fatal error" line of a FatalError and by a log prefix on the first
+// line. A FatalError without other errors is a group of its own. The messages of a group end at a
+// blank line, a line starting with a date, or the start of the next group. The JSON format is a
+// stream of serialized snapshots. The auto format reads text and also accepts serialized snapshots
+// logged on a single line.
+func parse(r io.Reader, format string) ([]group, error) {
+	switch format {
+	case formatJSON:
+		return parseJSON(r)
+	case formatAuto, formatText:
+		return parseText(r, format == formatAuto)
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// parseJSON reads a stream of serialized snapshots.
+func parseJSON(r io.Reader) ([]group, error) {
+	var groups []group
+
+	decoder := json.NewDecoder(r)
+	for {
+		var serialized groupJSON
+
+		err := decoder.Decode(&serialized)
+		if errors.Is(err, io.EOF) {
+			return groups, nil
+		}
+
+		if err != nil {
+			return groups, err
+		}
+
+		groups = append(groups, serialized.group())
+	}
+}
+
+// parseText reads the text written by the Error method of error status groups and, if
+// acceptJSON is true, serialized snapshots logged on a single line.
+func parseText(r io.Reader, acceptJSON bool) ([]group, error) {
+	var groups []group
+	var current *group
+	var fatal *string
+	var inHeader bool
+	var tripped string
+
+	flush := func() {
+		if current != nil {
+			groups = append(groups, *current)
+			current = nil
+		}
+	}
+
+	// flushFatal adds the fatal error read last as a group of its own when it is not followed by the
+	// errors of a group.
+	flushFatal := func() {
+		if fatal != nil {
+			groups = append(groups, group{messages: []string{*fatal}, statuses: map[int]int{}, tripped: tripped})
+			fatal = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if acceptJSON {
+			if serialized, ok := parseJSONLine(line); ok {
+				flush()
+				flushFatal()
+				groups = append(groups, serialized.group())
+				tripped = ""
+
+				continue
+			}
+		}
+
+		if i := strings.Index(line, lowestMarker); i >= 0 {
+			flush()
+
+			current = &group{hasStatus: true, statuses: map[int]int{}, tripped: tripped}
+			current.lowest, _ = parseBracketed(line[i+len(lowestMarker):])
+			inHeader = true
+			tripped = ""
+
+			if fatal != nil {
+				current.messages = append(current.messages, *fatal)
+				fatal = nil
+			}
+
+			continue
+		}
+
+		if current != nil && (strings.TrimSpace(line) == "" || newRecord.MatchString(line)) {
+			flush()
+		}
+
+		if current == nil {
+			flushFatal()
+
+			tripped = ""
+			if i := strings.Index(line, thresholdMarker); i >= 0 {
+				tripped = line[i+len(thresholdMarker):]
+			} else if i := strings.Index(line, fatalMarker); i >= 0 {
+				message := line[i+len(fatalMarker):]
+				fatal = &message
+				tripped = line[i:]
+			}
+
+			continue
+		}
+
+		switch {
+		case inHeader && strings.HasPrefix(line, highestMarker):
+			current.highest, _ = parseBracketed(line[len(highestMarker):])
+		case inHeader && strings.HasPrefix(line, countsMarker):
+			current.statuses = parseCounts(line[len(countsMarker):])
+		case inHeader && strings.HasPrefix(line, classesMarker):
+		default:
+			inHeader = false
+			current.messages = append(current.messages, strings.TrimLeft(line, " "))
+		}
+	}
+
+	flush()
+	flushFatal()
+
+	return groups, scanner.Err()
+}
+
+// parseJSONLine decodes the serialized snapshot logged on line, after an optional log prefix.
+func parseJSONLine(line string) (groupJSON, bool) {
+	var serialized groupJSON
+
+	i := strings.Index(line, "{")
+	if i < 0 || !strings.Contains(line, `"error_count"`) {
+		return serialized, false
+	}
+
+	if err := json.Unmarshal([]byte(line[i:]), &serialized); err != nil || serialized.ErrorCount == nil {
+		return serialized, false
+	}
+
+	return serialized, true
+}
+
+// parseBracketed parses the number at the start of s, which is terminated by a closing bracket.
+func parseBracketed(s string) (int, error) {
+	end := strings.Index(s, "]")
+	if end < 0 {
+		return 0, fmt.Errorf("missing closing bracket in %q", s)
+	}
+
+	return strconv.Atoi(s[:end])
+}
+
+// parseCounts parses the "404: 1, 503: 2]" remainder of a status counts line.
+func parseCounts(s string) map[int]int {
+	counts := map[int]int{}
+
+	s, _, _ = strings.Cut(s, "]")
+	if s == "" {
+		return counts
+	}
+
+	for _, pair := range strings.Split(s, ", ") {
+		code, count, ok := strings.Cut(pair, ": ")
+		if !ok {
+			continue
+		}
+
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			continue
+		}
+
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			continue
+		}
+
+		counts[status] += n
+	}
+
+	return counts
+}
+
+// group converts this serialized snapshot and its children into a single group whose messages are
+// prefixed with the path of the child group they belong to, the same way Error prefixes them.
+func (gj groupJSON) group() group {
+	g := group{
+		hasStatus: gj.LowestStatus != nil || gj.HighestStatus != nil,
+		statuses:  map[int]int{},
+		tripped:   gj.ThresholdExceeded,
+	}
+
+	if gj.LowestStatus != nil {
+		g.lowest = *gj.LowestStatus
+	}
+
+	if gj.HighestStatus != nil {
+		g.highest = *gj.HighestStatus
+	}
+
+	gj.collect(&g, "")
+
+	return g
+}
+
+// collect adds the messages, statuses and warnings of this serialized snapshot and its children
+// to g.
+func (gj groupJSON) collect(g *group, path string) {
+	for _, message := range gj.Errors {
+		g.messages = append(g.messages, prefixed(path, message))
+	}
+
+	for _, warning := range gj.Warnings {
+		g.warnings = append(g.warnings, prefixed(path, warning))
+	}
+
+	for _, status := range gj.Statuses {
+		g.statuses[status]++
+	}
+
+	for _, child := range gj.Groups {
+		childPath := child.Name
+		if path != "" {
+			childPath = path + "/" + child.Name
+		}
+
+		child.collect(g, childPath)
+	}
+}
+
+// prefixed returns message prefixed with path, unless path is empty.
+func prefixed(path, message string) string {
+	if path == "" {
+		return message
+	}
+
+	return path + ": " + message
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"github.com/seantcanavan/error_group"
+	"strings"
+	"testing"
+)
+
+// newSearchGroup returns a snapshot of an error status group with a child group, the way a handler
+// fanning out to several services would build it.
+func newSearchGroup(opts ...error_group.Option) error_group.StatusSnapshot {
+	students := error_group.NewErrorStatusGroup()
+	students.AddStatusAndError(503, errors.New("students unavailable"))
+
+	esg := error_group.NewErrorStatusGroup(opts...)
+	esg.AddStatus(200)
+	esg.AddStatusAndError(404, errors.New("teacher 42 not found"))
+	esg.AddGroup("students", students)
+
+	return esg.Snapshot()
+}
+
+func TestParse_Text(t *testing.T) {
+	log := "2024-05-01T10:00:00Z ERROR search failed: " + newSearchGroup().Error() + "\n" +
+		"2024-05-01T10:00:01Z INFO unrelated line\n" +
+		"2024-05-01T10:00:02Z ERROR search failed: " + newSearchGroup(error_group.MaxErrors(0)).ToError().Error() + "\n\n" +
+		"lowest status: [500]\nhighest status: [500]\nlegacy message without counts\n"
+
+	groups, err := parse(strings.NewReader(log), formatText)
+
+	t.Run("verify every group is reconstructed", func(t *testing.T) {
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(groups))
+	})
+	t.Run("verify the header is parsed", func(t *testing.T) {
+		assert.Equal(t, 200, groups[0].lowest)
+		assert.Equal(t, 503, groups[0].highest)
+		assert.DeepEqual(t, map[int]int{200: 1, 404: 1, 503: 1}, groups[0].statuses)
+	})
+	t.Run("verify messages end at the next log record and keep their child group path", func(t *testing.T) {
+		assert.DeepEqual(t, []string{"teacher 42 not found", "students: students unavailable"}, groups[0].messages)
+	})
+	t.Run("verify a ThresholdExceededError marks the group as tripped", func(t *testing.T) {
+		assert.Equal(t, "", groups[0].tripped)
+		assert.Equal(t, "more than 0 errors", groups[1].tripped)
+		assert.Equal(t, 2, len(groups[1].messages))
+	})
+	t.Run("verify the format without status counts is still parsed", func(t *testing.T) {
+		assert.Equal(t, 500, groups[2].highest)
+		assert.Equal(t, 0, len(groups[2].statuses))
+		assert.DeepEqual(t, []string{"legacy message without counts"}, groups[2].messages)
+	})
+}
+
+func TestParse_Fatal(t *testing.T) {
+	withOthers := error_group.NewErrorStatusGroup()
+	withOthers.AddStatusAndError(404, errors.New("teacher 42 not found"))
+	withOthers.AddFatal(errors.New("corrupt index"))
+
+	alone := error_group.NewErrorStatusGroup()
+	alone.AddFatal(errors.New("database unreachable"))
+
+	log := "2024-05-01T10:00:00Z ERROR search failed: " + withOthers.ToError().Error() + "\n" +
+		"2024-05-01T10:00:01Z ERROR index failed: " + alone.ToError().Error() + "\n" +
+		"2024-05-01T10:00:02Z INFO unrelated line\n"
+
+	groups, err := parse(strings.NewReader(log), formatText)
+
+	t.Run("verify a FatalError followed by other errors is parsed as one group", func(t *testing.T) {
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(groups))
+		assert.Equal(t, "fatal error: corrupt index", groups[0].tripped)
+		assert.Equal(t, 404, groups[0].highest)
+		assert.DeepEqual(t, []string{"corrupt index", "teacher 42 not found"}, groups[0].messages)
+	})
+	t.Run("verify a FatalError without other errors is a group of its own", func(t *testing.T) {
+		assert.Equal(t, "fatal error: database unreachable", groups[1].tripped)
+		assert.DeepEqual(t, []string{"database unreachable"}, groups[1].messages)
+	})
+	t.Run("verify fatal errors are counted and their groups are tripped", func(t *testing.T) {
+		s := summarize(groups, false)
+		assert.Equal(t, 2, s.tripped)
+		assert.Equal(t, 3, s.errors)
+		assert.Equal(t, 1, s.messages["database unreachable"])
+	})
+}
+
+func TestParse_JSON(t *testing.T) {
+	data, err := json.Marshal(newSearchGroup())
+	assert.Nil(t, err)
+
+	t.Run("verify a stream of serialized groups is parsed", func(t *testing.T) {
+		groups, err := parse(strings.NewReader(string(data)+"\n"+string(data)), formatJSON)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(groups))
+		assert.Equal(t, 503, groups[0].highest)
+		assert.DeepEqual(t, map[int]int{200: 1, 404: 1, 503: 1}, groups[0].statuses)
+		assert.DeepEqual(t, []string{"teacher 42 not found", "students: students unavailable"}, groups[0].messages)
+	})
+	t.Run("verify auto accepts serialized groups logged on a single line", func(t *testing.T) {
+		groups, err := parse(strings.NewReader("2024-05-01T10:00:00Z ERROR "+string(data)+"\n"), formatAuto)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(groups))
+		assert.Equal(t, 2, len(groups[0].messages))
+	})
+	t.Run("verify malformed JSON is reported", func(t *testing.T) {
+		_, err := parse(strings.NewReader("{"), formatJSON)
+
+		assert.NotNil(t, err)
+	})
+	t.Run("verify an unknown format is reported", func(t *testing.T) {
+		_, err := parse(strings.NewReader(""), "xml")
+
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// digits matches the numbers that normalize replaces.
+var digits = regexp.MustCompile(`\d+`)
+
+// summary aggregates the groups of one run.
+type summary struct {
+	errors   int
+	groups   int
+	highest  map[int]int
+	messages map[string]int
+	statuses map[int]int
+	tripped  int
+	warnings int
+}
+
+// count is a value and the number of times it was seen.
+type count[T cmp.Ordered] struct {
+	n     int
+	value T
+}
+
+// summarize aggregates groups. If normalize is true, every number in a message is replaced with N
+// so that messages differing only in identifiers are counted together.
+func summarize(groups []group, normalize bool) summary {
+	s := summary{
+		groups:   len(groups),
+		highest:  map[int]int{},
+		messages: map[string]int{},
+		statuses: map[int]int{},
+	}
+
+	for _, g := range groups {
+		s.errors += len(g.messages)
+		s.warnings += len(g.warnings)
+
+		if g.tripped != "" {
+			s.tripped++
+		}
+
+		if g.hasStatus {
+			s.highest[g.highest]++
+		}
+
+		for status, n := range g.statuses {
+			s.statuses[status] += n
+		}
+
+		for _, message := range g.messages {
+			if normalize {
+				message = digits.ReplaceAllString(message, "N")
+			}
+
+			s.messages[message]++
+		}
+	}
+
+	return s
+}
+
+// classes returns the number of statuses in each status class.
+func (s summary) classes() map[string]int {
+	classes := map[string]int{}
+
+	for status, n := range s.statuses {
+		classes[statusClass(status)] += n
+	}
+
+	return classes
+}
+
+// write prints this summary to w, listing at most top messages.
+func (s summary) write(w io.Writer, top int) {
+	fmt.Fprintf(w, "groups: %d (%d tripped)\n", s.groups, s.tripped)
+	fmt.Fprintf(w, "errors: %d\n", s.errors)
+	fmt.Fprintf(w, "warnings: %d\n", s.warnings)
+
+	fmt.Fprintf(w, "\ntop messages:\n")
+	for _, c := range limit(sorted(s.messages), top) {
+		fmt.Fprintf(w, "%8d  %s\n", c.n, c.value)
+	}
+
+	total := 0
+	for _, n := range s.statuses {
+		total += n
+	}
+
+	fmt.Fprintf(w, "\nstatuses:\n")
+	for _, c := range byValue(s.statuses) {
+		fmt.Fprintf(w, "%8d  %d (%.1f%%)\n", c.n, c.value, percent(c.n, total))
+	}
+
+	fmt.Fprintf(w, "\nstatus classes:\n")
+	for _, c := range byValue(s.classes()) {
+		fmt.Fprintf(w, "%8d  %s (%.1f%%)\n", c.n, c.value, percent(c.n, total))
+	}
+
+	fmt.Fprintf(w, "\nhighest status per group:\n")
+	for _, c := range byValue(s.highest) {
+		fmt.Fprintf(w, "%8d  %d\n", c.n, c.value)
+	}
+}
+
+// writeDiff prints the differences between the before and after runs to w, listing at most top
+// messages ordered by how much their count changed.
+func writeDiff(w io.Writer, before, after summary, top int) {
+	fmt.Fprintf(w, "groups: %d -> %d (%+d)\n", before.groups, after.groups, after.groups-before.groups)
+	fmt.Fprintf(w, "tripped: %d -> %d (%+d)\n", before.tripped, after.tripped, after.tripped-before.tripped)
+	fmt.Fprintf(w, "errors: %d -> %d (%+d)\n", before.errors, after.errors, after.errors-before.errors)
+	fmt.Fprintf(w, "warnings: %d -> %d (%+d)\n", before.warnings, after.warnings, after.warnings-before.warnings)
+
+	fmt.Fprintf(w, "\nmessages:\n")
+	for _, c := range limit(changes(before.messages, after.messages), top) {
+		fmt.Fprintf(w, "%+8d  %s%s\n", c.n, c.value, tag(before.messages, after.messages, c.value))
+	}
+
+	fmt.Fprintf(w, "\nstatuses:\n")
+	for _, c := range changes(before.statuses, after.statuses) {
+		fmt.Fprintf(w, "%+8d  %d (%d -> %d)\n", c.n, c.value, before.statuses[c.value], after.statuses[c.value])
+	}
+
+	fmt.Fprintf(w, "\nhighest status per group:\n")
+	for _, c := range changes(before.highest, after.highest) {
+		fmt.Fprintf(w, "%+8d  %d (%d -> %d)\n", c.n, c.value, before.highest[c.value], after.highest[c.value])
+	}
+}
+
+// byValue returns the counts of counts ordered by value.
+func byValue[T cmp.Ordered](counts map[T]int) []count[T] {
+	ordered := make([]count[T], 0, len(counts))
+	for value, n := range counts {
+		ordered = append(ordered, count[T]{n: n, value: value})
+	}
+
+	slices.SortFunc(ordered, func(a, b count[T]) int {
+		return cmp.Compare(a.value, b.value)
+	})
+
+	return ordered
+}
+
+// changes returns, for every value whose count differs between before and after, the change of its
+// count, ordered by decreasing magnitude and then by value.
+func changes[T cmp.Ordered](before, after map[T]int) []count[T] {
+	deltas := map[T]int{}
+
+	for value, n := range after {
+		if n != before[value] {
+			deltas[value] = n - before[value]
+		}
+	}
+
+	for value, n := range before {
+		if _, ok := after[value]; !ok {
+			deltas[value] = -n
+		}
+	}
+
+	ordered := byValue(deltas)
+	slices.SortStableFunc(ordered, func(a, b count[T]) int {
+		return cmp.Compare(abs(b.n), abs(a.n))
+	})
+
+	return ordered
+}
+
+// sorted returns the counts of counts ordered by decreasing count and then by value.
+func sorted[T cmp.Ordered](counts map[T]int) []count[T] {
+	ordered := byValue(counts)
+	slices.SortStableFunc(ordered, func(a, b count[T]) int {
+		return cmp.Compare(b.n, a.n)
+	})
+
+	return ordered
+}
+
+// limit returns the first top elements of counts, or all of them if top is not positive.
+func limit[T cmp.Ordered](counts []count[T], top int) []count[T] {
+	if top > 0 && len(counts) > top {
+		return counts[:top]
+	}
+
+	return counts
+}
+
+// tag marks messages that only appear in one of the two runs.
+func tag(before, after map[string]int, message string) string {
+	switch {
+	case before[message] == 0:
+		return " [new]"
+	case after[message] == 0:
+		return " [gone]"
+	}
+
+	return ""
+}
+
+// percent returns n as a percentage of total.
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return 100 * float64(n) / float64(total)
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// statusClass returns the class status belongs to, the same way the status histogram of a group
+// classifies it.
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "other"
+	}
+
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"github.com/jgroeneveld/trial/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	groups := []group{
+		{hasStatus: true, highest: 503, messages: []string{"teacher 42 not found", "students unavailable"}, statuses: map[int]int{200: 1, 404: 1, 503: 1}},
+		{hasStatus: true, highest: 404, messages: []string{"teacher 7 not found"}, statuses: map[int]int{404: 1}, tripped: "more than 0 errors"},
+		{messages: []string{"students unavailable"}, warnings: []string{"cache miss"}},
+	}
+
+	t.Run("verify totals and distributions are aggregated", func(t *testing.T) {
+		s := summarize(groups, false)
+
+		assert.Equal(t, 3, s.groups)
+		assert.Equal(t, 1, s.tripped)
+		assert.Equal(t, 4, s.errors)
+		assert.Equal(t, 1, s.warnings)
+		assert.DeepEqual(t, map[int]int{200: 1, 404: 2, 503: 1}, s.statuses)
+		assert.DeepEqual(t, map[string]int{"2xx": 1, "4xx": 2, "5xx": 1}, s.classes())
+		assert.DeepEqual(t, map[int]int{404: 1, 503: 1}, s.highest)
+	})
+	t.Run("verify normalize counts messages differing only in numbers together", func(t *testing.T) {
+		s := summarize(groups, true)
+
+		assert.DeepEqual(t, map[string]int{"teacher N not found": 2, "students unavailable": 2}, s.messages)
+	})
+	t.Run("verify write lists the top messages first", func(t *testing.T) {
+		var out bytes.Buffer
+		summarize(groups, false).write(&out, 1)
+
+		assert.Equal(t, `groups: 3 (1 tripped)
+errors: 4
+warnings: 1
+
+top messages:
+       2  students unavailable
+
+statuses:
+       1  200 (25.0%)
+       2  404 (50.0%)
+       1  503 (25.0%)
+
+status classes:
+       1  2xx (25.0%)
+       2  4xx (50.0%)
+       1  5xx (25.0%)
+
+highest status per group:
+       1  404
+       1  503
+`, out.String())
+	})
+}
+
+func TestWriteDiff(t *testing.T) {
+	before := summarize([]group{
+		{hasStatus: true, highest: 503, messages: []string{"students unavailable", "students unavailable"}, statuses: map[int]int{503: 2}},
+		{hasStatus: true, highest: 404, messages: []string{"teacher not found"}, statuses: map[int]int{404: 1}},
+	}, false)
+	after := summarize([]group{
+		{hasStatus: true, highest: 504, messages: []string{"search timed out", "students unavailable"}, statuses: map[int]int{503: 1, 504: 1}},
+	}, false)
+
+	var out bytes.Buffer
+	writeDiff(&out, before, after, 0)
+
+	t.Run("verify changes are ordered by magnitude and new or gone messages are marked", func(t *testing.T) {
+		assert.Equal(t, `groups: 2 -> 1 (-1)
+tripped: 0 -> 0 (+0)
+errors: 3 -> 2 (-1)
+warnings: 0 -> 0 (+0)
+
+messages:
+      +1  search timed out [new]
+      -1  students unavailable
+      -1  teacher not found [gone]
+
+statuses:
+      -1  404 (1 -> 0)
+      -1  503 (2 -> 1)
+      +1  504 (0 -> 1)
+
+highest status per group:
+      -1  404 (1 -> 0)
+      -1  503 (1 -> 0)
+      +1  504 (0 -> 1)
+`, out.String())
+	})
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.log")
+	after := filepath.Join(dir, "after.log")
+
+	assert.Nil(t, os.WriteFile(before, []byte(newSearchGroup().Error()+"\n"), 0o600))
+	assert.Nil(t, os.WriteFile(after, []byte("lowest status: [200]\nhighest status: [200]\n"), 0o600))
+
+	t.Run("verify standard input is read when no file is named", func(t *testing.T) {
+		var out bytes.Buffer
+		err := run([]string{"-top", "1"}, strings.NewReader(newSearchGroup().Error()), &out)
+
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(out.String(), "groups: 1 (0 tripped)\nerrors: 2\n"))
+	})
+	t.Run("verify -diff compares two files", func(t *testing.T) {
+		var out bytes.Buffer
+		err := run([]string{"-diff", before, after}, nil, &out)
+
+		assert.Nil(t, err)
+		assert.True(t, strings.Contains(out.String(), "-1  teacher 42 not found [gone]"))
+	})
+	t.Run("verify -diff requires two files", func(t *testing.T) {
+		assert.NotNil(t, run([]string{"-diff", before}, nil, &bytes.Buffer{}))
+	})
+	t.Run("verify a missing file is reported", func(t *testing.T) {
+		assert.NotNil(t, run([]string{filepath.Join(dir, "missing.log")}, nil, &bytes.Buffer{}))
+	})
+}
@@ -0,0 +1,15 @@
+module github.com/seantcanavan/error_group/cmd/groupcheck
+
+go 1.23
+
+require (
+	github.com/seantcanavan/error_group/groupcheck v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.30.0
+)
+
+require (
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+)
+
+replace github.com/seantcanavan/error_group/groupcheck => ../../groupcheck
@@ -0,0 +1,15 @@
+// Command groupcheck reports misuses of error groups and error status groups. It can be run
+// directly or as a vet tool:
+//
+//	groupcheck ./...
+//	go vet -vettool=$(which groupcheck) ./...
+package main
+
+import (
+	"github.com/seantcanavan/error_group/groupcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(groupcheck.Analyzer)
+}
@@ -0,0 +1,30 @@
+package error_group
+
+// ErrorCollector is the set of methods code collecting errors from concurrent work typically needs.
+// It is satisfied by *ErrorGroup and can be satisfied by test doubles, so that functions and struct
+// fields do not have to depend on the concrete group type.
+type ErrorCollector interface {
+	Add(err error)
+	Go(f func() error) bool
+	Len() int
+	ToError() error
+	Wait() error
+}
+
+// StatusCollector is the set of methods code collecting errors and status values from concurrent
+// work typically needs. It is satisfied by *ErrorStatusGroup and can be satisfied by test doubles.
+type StatusCollector interface {
+	AddError(err error)
+	AddStatus(status int)
+	AddStatusAndError(status int, err error)
+	Go(f func() (int, error)) bool
+	LenErrors() int
+	LenStatuses() int
+	ToStatusAndError() (int, error)
+	Wait() (int, error)
+}
+
+var (
+	_ ErrorCollector  = (*ErrorGroup)(nil)
+	_ StatusCollector = (*ErrorStatusGroup)(nil)
+)
@@ -0,0 +1,153 @@
+package error_group
+
+import (
+	"context"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"testing"
+	"time"
+)
+
+// fakeStatusCollector is a test double recording the values added to it.
+type fakeStatusCollector struct {
+	errs     []error
+	statuses []int
+}
+
+func (f *fakeStatusCollector) AddError(err error) {
+	f.errs = append(f.errs, err)
+}
+
+func (f *fakeStatusCollector) AddStatus(status int) {
+	f.statuses = append(f.statuses, status)
+}
+
+func (f *fakeStatusCollector) AddStatusAndError(status int, err error) {
+	f.AddStatus(status)
+	f.AddError(err)
+}
+
+func (f *fakeStatusCollector) Go(fn func() (int, error)) bool {
+	f.AddStatusAndError(fn())
+	return true
+}
+
+func (f *fakeStatusCollector) LenErrors() int {
+	return len(f.errs)
+}
+
+func (f *fakeStatusCollector) LenStatuses() int {
+	return len(f.statuses)
+}
+
+func (f *fakeStatusCollector) ToStatusAndError() (int, error) {
+	return 0, errors.Join(f.errs...)
+}
+
+func (f *fakeStatusCollector) Wait() (int, error) {
+	return f.ToStatusAndError()
+}
+
+// searchAll fans out to every search function and collects their results into sc.
+func searchAll(sc StatusCollector, searches ...func() (int, error)) (int, error) {
+	for _, search := range searches {
+		sc.Go(search)
+	}
+
+	return sc.Wait()
+}
+
+func TestErrorGroup_ZeroValue(t *testing.T) {
+	var eg ErrorGroup
+
+	t.Run("verify an empty zero value reports no errors", func(t *testing.T) {
+		assert.Equal(t, 0, eg.Len())
+		assert.Nil(t, eg.ToError())
+		assert.False(t, eg.Tripped())
+	})
+	t.Run("verify the zero value collects errors from Add and Go", func(t *testing.T) {
+		message := generateRandomString(10)
+
+		eg.Add(errors.New(message))
+		eg.Go(func() error { return errors.New(message) })
+		eg.GoWithTimeout("slow", time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		assert.NotNil(t, eg.Wait())
+		assert.Equal(t, 3, eg.Len())
+		assert.Equal(t, message, eg.First().Error())
+	})
+	t.Run("verify Reset() empties the zero value", func(t *testing.T) {
+		eg.Reset()
+
+		assert.Equal(t, 0, eg.Len())
+	})
+}
+
+func TestErrorStatusGroup_ZeroValue(t *testing.T) {
+	var esg ErrorStatusGroup
+
+	t.Run("verify an empty zero value reports the default statuses", func(t *testing.T) {
+		status, err := esg.ToStatusAndError()
+
+		assert.Equal(t, 200, status)
+		assert.Nil(t, err)
+		assert.Equal(t, 200, esg.LowestStatus())
+		assert.Equal(t, 200, esg.Snapshot().HighestStatus())
+	})
+	t.Run("verify the zero value behaves like a constructed group", func(t *testing.T) {
+		constructed := NewErrorStatusGroup()
+
+		for _, group := range []*ErrorStatusGroup{&esg, constructed} {
+			group.AddStatus(500)
+			group.Go(func() (int, error) { return 404, errors.New("not found") })
+			group.Wait()
+		}
+
+		assert.Equal(t, constructed.Error(), esg.Error())
+		assert.Equal(t, 200, esg.LowestStatus())
+		assert.Equal(t, 500, esg.HighestStatus())
+	})
+	t.Run("verify Reset() restores the default statuses", func(t *testing.T) {
+		esg.Reset()
+
+		assert.Equal(t, 200, esg.HighestStatus())
+		assert.Equal(t, 0, esg.LenStatuses())
+	})
+}
+
+func TestStatusCollector(t *testing.T) {
+	search := func() (int, error) {
+		return 503, errors.New("unavailable")
+	}
+
+	t.Run("verify the real group and a test double are interchangeable", func(t *testing.T) {
+		fake := &fakeStatusCollector{}
+
+		for _, sc := range []StatusCollector{NewErrorStatusGroup(), fake} {
+			_, err := searchAll(sc, search, search)
+
+			assert.NotNil(t, err)
+			assert.Equal(t, 2, sc.LenErrors())
+		}
+
+		assert.DeepEqual(t, []int{503, 503}, fake.statuses)
+	})
+	t.Run("verify the exported types can be used as struct fields", func(t *testing.T) {
+		handler := struct {
+			errors   ErrorCollector
+			statuses *ErrorStatusGroup
+		}{
+			errors:   &ErrorGroup{},
+			statuses: &ErrorStatusGroup{},
+		}
+
+		handler.errors.Add(errors.New("failed"))
+		handler.statuses.AddStatus(201)
+
+		assert.Equal(t, 1, handler.errors.Len())
+		assert.Equal(t, 201, handler.statuses.HighestStatus())
+	})
+}
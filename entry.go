@@ -0,0 +1,31 @@
+package error_group
+
+// Entry is a single value saved to an error status group instance. Values added with AddError only
+// carry an error, values added with AddStatus only carry a status and values added with
+// AddStatusAndError carry both. Severity tells warnings and fatal errors apart from regular ones and
+// Optional marks values added with AddOptionalStatusAndError or GoOptional.
+type Entry struct {
+	Err       error
+	HasStatus bool
+	Optional  bool
+	Severity  Severity
+	Status    int
+}
+
+// splitEntries returns the statuses and the errors carried by entries as two new slices.
+func splitEntries(entries []Entry, statusCount, errorCount int) ([]int, []error) {
+	dupErrors := make([]error, 0, errorCount)
+	dupStatuses := make([]int, 0, statusCount)
+
+	for _, entry := range entries {
+		if entry.HasStatus {
+			dupStatuses = append(dupStatuses, entry.Status)
+		}
+
+		if entry.Err != nil {
+			dupErrors = append(dupErrors, entry.Err)
+		}
+	}
+
+	return dupStatuses, dupErrors
+}
@@ -1,243 +1,639 @@
 package error_group
 
 import (
-	"errors"
-	"fmt"
-	"strings"
+	"context"
+	"slices"
 	"sync"
 )
 
-type errorStatusGroup struct {
-	errors        []error
-	errorsMutex   *sync.Mutex
+// defaultStatus is the lowest and highest status value of an error status group instance before
+// any status has been added. Adding a status only widens the range around it.
+const defaultStatus = 200
+
+// ErrorStatusGroup collects errors and status values from any number of goroutines. It guards its
+// entries with a single mutex so that every method observes the errors, statuses and the lowest /
+// highest status values in one consistent state. The zero value is an empty error status group
+// instance without any options, ready to use. An ErrorStatusGroup must not be copied after first
+// use.
+type ErrorStatusGroup struct {
+	cancel        context.CancelCauseFunc
+	closed        bool
+	config        config
+	ctx           context.Context
+	entries       []Entry
+	errorCount    int
+	fatal         error
+	groups        []namedErrorStatusGroup
 	highestStatus int
+	hooks         hooks
+	lateWrites    int
 	lowestStatus  int
-	statuses      []int
-	statusesMutex *sync.Mutex
+	mutex         sync.Mutex
+	optional      []Entry
+	running       int
+	seqs          []uint64
+	stats         taskStats
+	statusCount   int
+	submitted     uint64
+	subscribers   subscribers
+	tasks         sync.WaitGroup
+	tripReason    string
+	warnings      []Entry
 }
 
-//goland:noinspection GoExportedFuncWithUnexportedType
-func NewErrorStatusGroup() *errorStatusGroup {
-	errorMutex := sync.Mutex{}
-	statusMutex := sync.Mutex{}
+// namedErrorStatusGroup is a child error status group added to a parent with AddGroup.
+type namedErrorStatusGroup struct {
+	group *ErrorStatusGroup
+	name  string
+}
+
+// NewErrorStatusGroup returns a new error status group instance configured with opts.
+func NewErrorStatusGroup(opts ...Option) *ErrorStatusGroup {
+	esg := &ErrorStatusGroup{
+		config: newConfig(opts),
+	}
 
-	return &errorStatusGroup{
-		errorsMutex:   &errorMutex,
-		highestStatus: 200,
-		lowestStatus:  200,
-		statusesMutex: &statusMutex,
+	if esg.config.registry != nil {
+		esg.config.registry.add(esg, esg.config.name)
 	}
+
+	return esg
 }
 
-// AddError adds an error to this error status group instance.
-func (esg *errorStatusGroup) AddError(err error) {
+// AddError adds an error to this error status group instance. If the group was created with
+// InferStatuses and the error maps to a status, the error is added together with that status as
+// if by AddStatusAndError.
+func (esg *ErrorStatusGroup) AddError(err error) {
 	if err == nil {
 		return
 	}
 
-	esg.errorsMutex.Lock()
-	defer esg.errorsMutex.Unlock()
+	if esg.config.statusMap != nil {
+		if status, ok := esg.config.statusMap.Lookup(err); ok {
+			esg.record(0, []Entry{{Err: err, HasStatus: true, Status: status}}, nil)
+			return
+		}
+	}
 
-	esg.errors = append(esg.errors, err)
+	esg.record(0, []Entry{{Err: err}}, nil)
 }
 
-// AddStatus adds a status to this error status group instance. Status values should be
-// 0 or greater. Negative status values will be ignored.
-func (esg *errorStatusGroup) AddStatus(status int) {
-	esg.statusesMutex.Lock()
-	defer esg.statusesMutex.Unlock()
-
-	if status < esg.lowestStatus {
-		esg.lowestStatus = status
+// AddGroup adds child as a named child of this error status group instance. Errors added to
+// child, before or after this call, are rendered by Error as "name: message" lines indented
+// beneath the errors of this group and are reachable through Unwrap. The status values of
+// child roll up into HighestStatus and LowestStatus. Entry accessors such as LenErrors, All,
+// FirstError and LastError only consider the values added to this group directly. AddGroup
+// panics if adding child would create a cycle.
+func (esg *ErrorStatusGroup) AddGroup(name string, child *ErrorStatusGroup) {
+	if child == nil {
+		return
 	}
 
-	if status > esg.highestStatus {
-		esg.highestStatus = status
+	if child.contains(esg) {
+		panic("error_group: AddGroup would create a cycle")
 	}
 
-	esg.statuses = append(esg.statuses, status)
+	esg.record(0, nil, []namedErrorStatusGroup{{group: child, name: name}})
+}
+
+// AddStatus adds a status to this error status group instance. Status values should be
+// 0 or greater. Negative status values will be ignored.
+func (esg *ErrorStatusGroup) AddStatus(status int) {
+	esg.record(0, []Entry{{HasStatus: true, Status: status}}, nil)
 }
 
 // AddStatusAndError adds an error and a status value to this error status group instance.
 // Status values should be 0 or greater. Negative status values will be ignored.
-func (esg *errorStatusGroup) AddStatusAndError(status int, err error) {
-	var wg sync.WaitGroup
-	wg.Add(2)
+func (esg *ErrorStatusGroup) AddStatusAndError(status int, err error) {
+	esg.record(0, []Entry{{Err: err, HasStatus: true, Status: status}}, nil)
+}
 
-	go func() {
-		esg.AddStatus(status)
-		wg.Done()
-	}()
+// addEntryLocked records entry and updates the counts and the lowest and highest status values.
+// The caller must hold esg.mutex.
+func (esg *ErrorStatusGroup) addEntryLocked(entry Entry) {
+	if entry.HasStatus {
+		if esg.statusCount == 0 {
+			esg.lowestStatus, esg.highestStatus = defaultStatus, defaultStatus
+		}
 
-	go func() {
-		esg.AddError(err)
-		wg.Done()
-	}()
+		if entry.Status < esg.lowestStatus {
+			esg.lowestStatus = entry.Status
+		}
 
-	wg.Wait()
-}
+		if entry.Status > esg.highestStatus {
+			esg.highestStatus = entry.Status
+		}
 
-// All returns two new slices - one containing every error value in this error status group instance.
-// The other containing every status value in this error status group instance.
-func (esg *errorStatusGroup) All() ([]int, []error) {
-	esg.errorsMutex.Lock()
-	esg.statusesMutex.Lock()
-	defer esg.errorsMutex.Unlock()
-	defer esg.statusesMutex.Unlock()
+		esg.statusCount++
+	}
 
-	dupErrors := make([]error, len(esg.errors))
-	dupStatuses := make([]int, len(esg.statuses))
+	if entry.Err != nil {
+		esg.errorCount++
+	}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
+	esg.entries = append(esg.entries, entry)
+}
 
-	go func() {
-		copy(dupErrors, esg.errors)
-		wg.Done()
-	}()
+// storeLocked saves entry to this error status group instance. Warnings and optional entries are
+// saved apart from the other entries, which are added with addEntryLocked. It reports whether entry
+// was added with addEntryLocked. The caller must hold esg.mutex.
+func (esg *ErrorStatusGroup) storeLocked(entry Entry) bool {
+	switch {
+	case entry.Severity == SeverityWarning:
+		esg.warnings = append(esg.warnings, entry)
+	case entry.Optional:
+		esg.optional = append(esg.optional, entry)
+	default:
+		esg.addEntryLocked(entry)
+
+		return true
+	}
 
-	go func() {
-		copy(dupStatuses, esg.statuses)
-		wg.Done()
-	}()
+	return false
+}
 
-	wg.Wait()
+// All returns two new slices - one containing every error value in this error status group instance.
+// The other containing every status value in this error status group instance. Both are in the
+// ordering the group was created with.
+func (esg *ErrorStatusGroup) All() ([]int, []error) {
+	if esg.config.order.kind != orderInsertion {
+		return esg.Snapshot().All()
+	}
 
-	return dupStatuses, dupErrors
-}
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
 
-// Error fulfills the builtin.Error interface and returns a concatenated string of all the errors in this
-// error status group instance. It will also contain the highest and lowest status values encountered.
-func (esg *errorStatusGroup) Error() string {
-	esg.errorsMutex.Lock()
-	esg.statusesMutex.Lock()
-	defer esg.errorsMutex.Unlock()
-	defer esg.statusesMutex.Unlock()
+	return splitEntries(esg.entries, esg.statusCount, esg.errorCount)
+}
 
-	if len(esg.errors) < 1 {
-		return ""
+// Close seals this error status group instance. Values added after Close are discarded and counted
+// by LateWrites, or cause a panic if the group was created with PanicOnLateWrite. Close should be
+// called once the group has been read for the last time, e.g. right before returning ToStatusAndError.
+// Closing a group also closes every channel returned by Subscribe and removes the group from the
+// registry it was created with.
+func (esg *ErrorStatusGroup) Close() {
+	esg.mutex.Lock()
+	esg.closed = true
+	esg.subscribers.close()
+	esg.mutex.Unlock()
+
+	if esg.config.registry != nil {
+		esg.config.registry.remove(esg)
 	}
+}
 
-	sb := strings.Builder{}
+// Closed reports whether Close has been called on this error status group instance.
+func (esg *ErrorStatusGroup) Closed() bool {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
 
-	sb.WriteString(fmt.Sprintf("lowest status: [%d]", esg.lowestStatus))
-	sb.WriteString("\n")
-	sb.WriteString(fmt.Sprintf("highest status: [%d]", esg.highestStatus))
-	sb.WriteString("\n")
+	return esg.closed
+}
 
-	for _, currentError := range esg.errors {
-		sb.WriteString(currentError.Error())
-		sb.WriteString("\n")
+// Drain atomically captures the current state of this error status group instance and clears it,
+// including the lowest and highest status values. Values added concurrently are either part of the
+// returned snapshot or remain in the group afterwards, they are never lost. Child groups are
+// detached from this group and captured in the snapshot. The tripped state is reported by the
+// snapshot and cleared from the group, so a group that tripped accepts tasks again, but a context
+// returned by NewErrorStatusGroupWithContext stays cancelled.
+func (esg *ErrorStatusGroup) Drain() StatusSnapshot {
+	esg.mutex.Lock()
+	drained := &ErrorStatusGroup{
+		config:        esg.config,
+		entries:       esg.entries,
+		errorCount:    esg.errorCount,
+		fatal:         esg.fatal,
+		groups:        esg.groups,
+		highestStatus: esg.highestStatus,
+		lowestStatus:  esg.lowestStatus,
+		optional:      esg.optional,
+		seqs:          esg.seqs,
+		statusCount:   esg.statusCount,
+		tripReason:    esg.tripReason,
+		warnings:      esg.warnings,
 	}
+	esg.clearLocked()
+	esg.fatal = nil
+	esg.tripReason = ""
+	esg.mutex.Unlock()
+
+	return drained.Snapshot()
+}
+
+// Dropped returns the number of times an entry could not be delivered to a channel returned by
+// Subscribe because its buffer was full.
+func (esg *ErrorStatusGroup) Dropped() int {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
 
-	return strings.TrimSuffix(sb.String(), "\n")
+	return esg.subscribers.dropped
+}
+
+// Error fulfills the builtin.Error interface and returns a concatenated string of all the errors in this
+// error status group instance followed by the errors of any child groups. It will also contain the
+// highest and lowest status values encountered across the whole hierarchy.
+func (esg *ErrorStatusGroup) Error() string {
+	return esg.Snapshot().Error()
 }
 
 // FirstError returns the first error value saved to this error status group instance.
 // Since this library is thread safe - the first error value saved is not deterministic
 // if the library is used in a multithreaded environment.
-func (esg *errorStatusGroup) FirstError() error {
-	esg.errorsMutex.Lock()
-	defer esg.errorsMutex.Unlock()
+func (esg *ErrorStatusGroup) FirstError() error {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	for _, entry := range esg.entries {
+		if entry.Err != nil {
+			return entry.Err
+		}
+	}
 
-	return esg.errors[0]
+	panic("error_group: FirstError called on a group without errors")
 }
 
 // FirstStatus returns the first status value saved to this error status group instance.
 // Since this library is thread safe - the first status value saved is not deterministic
 // if the library is used in a multithreaded environment.
-func (esg *errorStatusGroup) FirstStatus() int {
-	esg.statusesMutex.Lock()
-	defer esg.statusesMutex.Unlock()
+func (esg *ErrorStatusGroup) FirstStatus() int {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	for _, entry := range esg.entries {
+		if entry.HasStatus {
+			return entry.Status
+		}
+	}
+
+	panic("error_group: FirstStatus called on a group without statuses")
+}
+
+// statusRangeLocked returns the lowest and highest status value saved to this error status group
+// instance, not counting its child groups. The caller must hold esg.mutex.
+func (esg *ErrorStatusGroup) statusRangeLocked() (int, int) {
+	if esg.statusCount == 0 {
+		return defaultStatus, defaultStatus
+	}
 
-	return esg.statuses[0]
+	return esg.lowestStatus, esg.highestStatus
 }
 
-// HighestStatus returns the current highest status value saved to this error status group instance. Subsequent
-// calls to AddStatus or AddStatusAndError can cause the value returned here to no longer be accurate.
-func (esg *errorStatusGroup) HighestStatus() int {
-	esg.statusesMutex.Lock()
-	defer esg.statusesMutex.Unlock()
+// HighestStatus returns the current highest status value saved to this error status group instance or
+// any of its child groups. Subsequent calls to AddStatus or AddStatusAndError can cause the value
+// returned here to no longer be accurate.
+func (esg *ErrorStatusGroup) HighestStatus() int {
+	esg.mutex.Lock()
+	_, highestStatus := esg.statusRangeLocked()
+	groups := esg.groups[:len(esg.groups):len(esg.groups)]
+	esg.mutex.Unlock()
+
+	for _, child := range groups {
+		if childStatus := child.group.HighestStatus(); childStatus > highestStatus {
+			highestStatus = childStatus
+		}
+	}
 
-	return esg.highestStatus
+	return highestStatus
 }
 
 // LastError returns the last error value saved to this error status group instance. Subsequent calls
 // to AddError or AddStatusAndError can cause the value returned here to no longer be the last.
-func (esg *errorStatusGroup) LastError() error {
-	esg.errorsMutex.Lock()
-	defer esg.errorsMutex.Unlock()
+func (esg *ErrorStatusGroup) LastError() error {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	for i := len(esg.entries) - 1; i >= 0; i-- {
+		if esg.entries[i].Err != nil {
+			return esg.entries[i].Err
+		}
+	}
 
-	return esg.errors[len(esg.errors)-1]
+	panic("error_group: LastError called on a group without errors")
 }
 
 // LastStatus returns the last status value saved to this error status group instance. Subsequent calls
 // to AddStatus or AddStatusAndError can cause the value returned here to no longer be the last.
-func (esg *errorStatusGroup) LastStatus() int {
-	esg.statusesMutex.Lock()
-	defer esg.statusesMutex.Unlock()
+func (esg *ErrorStatusGroup) LastStatus() int {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	for i := len(esg.entries) - 1; i >= 0; i-- {
+		if esg.entries[i].HasStatus {
+			return esg.entries[i].Status
+		}
+	}
 
-	return esg.statuses[len(esg.statuses)-1]
+	panic("error_group: LastStatus called on a group without statuses")
+}
+
+// LateWrites returns the number of values that were discarded because they were added to this
+// error status group instance after Close had been called.
+func (esg *ErrorStatusGroup) LateWrites() int {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	return esg.lateWrites
 }
 
 // LenErrors returns the (current) number of error values saved to this error status group instance.
 // Subsequent calls to AddError or AddStatusAndError can cause the value returned here to no longer be accurate.
-func (esg *errorStatusGroup) LenErrors() int {
-	esg.errorsMutex.Lock()
-	defer esg.errorsMutex.Unlock()
+func (esg *ErrorStatusGroup) LenErrors() int {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
 
-	return len(esg.errors)
+	return esg.errorCount
 }
 
 // LenStatuses returns the (current) number of status values saved to this error status group instance.
 // Subsequent calls to AddStatus or AddStatusAndError can cause the value returned here to no longer be accurate.
-func (esg *errorStatusGroup) LenStatuses() int {
-	esg.statusesMutex.Lock()
-	defer esg.statusesMutex.Unlock()
+func (esg *ErrorStatusGroup) LenStatuses() int {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
 
-	return len(esg.statuses)
+	return esg.statusCount
 }
 
-// LowestStatus returns the current lowest status value saved to this error status group instance. Subsequent
-// calls to AddStatus or AddStatusAndError can cause the value returned here to no longer be accurate.
-func (esg *errorStatusGroup) LowestStatus() int {
-	esg.statusesMutex.Lock()
-	defer esg.statusesMutex.Unlock()
+// LowestStatus returns the current lowest status value saved to this error status group instance or
+// any of its child groups. Subsequent calls to AddStatus or AddStatusAndError can cause the value
+// returned here to no longer be accurate.
+func (esg *ErrorStatusGroup) LowestStatus() int {
+	esg.mutex.Lock()
+	lowestStatus, _ := esg.statusRangeLocked()
+	groups := esg.groups[:len(esg.groups):len(esg.groups)]
+	esg.mutex.Unlock()
+
+	for _, child := range groups {
+		if childStatus := child.group.LowestStatus(); childStatus < lowestStatus {
+			lowestStatus = childStatus
+		}
+	}
 
-	return esg.lowestStatus
+	return lowestStatus
 }
 
-// ToStatusAndError returns the current highest status value in conjunction with a combined error value representing
-// all the errors currently saved to this error status group. This should be used when execution is finished and a
-// summary result is ready to be returned to the caller for processing.
-func (esg *errorStatusGroup) ToStatusAndError() (int, error) {
-	var wg sync.WaitGroup
-	var highestStatus int
-	var err error
-	wg.Add(2)
+// Merge adds every error, status, warning and child group currently saved to other to this error
+// status group instance. Values added to other after Merge returns are not reflected in this group.
+func (esg *ErrorStatusGroup) Merge(other *ErrorStatusGroup) {
+	if other == nil {
+		return
+	}
 
-	go func() {
-		highestStatus = esg.HighestStatus()
-		wg.Done()
-	}()
+	other.mutex.Lock()
+	entries := other.entries[:len(other.entries):len(other.entries)]
+	groups := other.groups[:len(other.groups):len(other.groups)]
+	optional := other.optional[:len(other.optional):len(other.optional)]
+	warnings := other.warnings[:len(other.warnings):len(other.warnings)]
+	other.mutex.Unlock()
+
+	for _, child := range groups {
+		if child.group.contains(esg) {
+			panic("error_group: Merge would create a cycle")
+		}
+	}
 
-	go func() {
-		err = esg.ToError()
-		wg.Done()
-	}()
+	esg.record(0, slices.Concat(entries, optional, warnings), groups)
+}
 
-	wg.Wait()
+// OnError registers hook to be called with every error value added to this error status group
+// instance from now on. Hooks are called synchronously, in registration order, by the goroutine
+// that added the value once the group's lock has been released, so a slow hook slows down that
+// goroutine but may safely call back into the group.
+func (esg *ErrorStatusGroup) OnError(hook func(error)) {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
 
-	return highestStatus, err
+	esg.hooks.errorHooks = append(esg.hooks.errorHooks, hook)
+}
+
+// OnStatus registers hook to be called with every status value added to this error status group
+// instance from now on. Hooks are called the same way as those registered with OnError.
+func (esg *ErrorStatusGroup) OnStatus(hook func(int)) {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	esg.hooks.statusHooks = append(esg.hooks.statusHooks, hook)
+}
+
+// Reset removes every error, status, warning and child group from this error status group instance,
+// restores the lowest and highest status values to their initial value and clears its tripped
+// state. It reopens the group if it was closed so that it can be reused, adding it back to the
+// registry it was created with. A context returned by NewErrorStatusGroupWithContext that has
+// already been cancelled stays cancelled.
+func (esg *ErrorStatusGroup) Reset() {
+	esg.mutex.Lock()
+	esg.resetLocked()
+	esg.mutex.Unlock()
+
+	if esg.config.registry != nil {
+		esg.config.registry.add(esg, esg.config.name)
+	}
+}
+
+// Subscribe returns a channel that receives every entry added to this error status group instance
+// from now on. Delivery never blocks the goroutine adding the entry: entries are sent in the order
+// they were added until the channel's buffer is full, after which they are discarded and counted by
+// Dropped. The channel is closed by Close. Use OnError and OnStatus instead when every value must be
+// observed.
+func (esg *ErrorStatusGroup) Subscribe() <-chan Entry {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	if esg.closed {
+		return closedSubscription()
+	}
+
+	return esg.subscribers.add()
+}
+
+// ToStatusAndError returns the current highest status value in conjunction with a combined error value representing
+// all the errors currently saved to this error status group. This should be used when execution is finished and a
+// summary result is ready to be returned to the caller for processing. The first call finalizes the
+// group, see Observe.
+func (esg *ErrorStatusGroup) ToStatusAndError() (int, error) {
+	snapshot := esg.Snapshot()
+	esg.finalize(snapshot)
+
+	return snapshot.ToStatusAndError()
 }
 
 // ToError is a convenience function that converts the errors and statuses contained
 // in this error status group into one single error. This is useful for returning the ErrorStatusGroup
 // object instance as a single generic builtin.Error interface instance.
-func (esg *errorStatusGroup) ToError() error {
-	errMessage := esg.Error()
-	if errMessage == "" {
+//
+// Once the group has tripped one of its thresholds ToError returns a *ThresholdExceededError
+// wrapping the collected errors instead, or a *FatalError if it was tripped by AddFatal. The first
+// call finalizes the group, see Observe.
+func (esg *ErrorStatusGroup) ToError() error {
+	snapshot := esg.Snapshot()
+	esg.finalize(snapshot)
+
+	return snapshot.ToError()
+}
+
+// Tripped reports whether this error status group instance has crossed one of the thresholds
+// configured with MaxErrors, MaxErrorRate or StatusThreshold or had an error added with AddFatal.
+func (esg *ErrorStatusGroup) Tripped() bool {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	return esg.tripReason != ""
+}
+
+// Unwrap returns the errors saved to this error status group instance followed by one error for
+// each child group that holds errors. It allows errors.Is and errors.As to traverse the whole
+// hierarchy of groups.
+func (esg *ErrorStatusGroup) Unwrap() []error {
+	return esg.Snapshot().Unwrap()
+}
+
+// clearLocked removes every value from this error status group instance. Snapshots share the
+// backing arrays of the slices so they are replaced rather than truncated. The caller must hold
+// esg.mutex.
+func (esg *ErrorStatusGroup) clearLocked() {
+	esg.entries = nil
+	esg.errorCount = 0
+	esg.groups = nil
+	esg.highestStatus = 0
+	esg.lowestStatus = 0
+	esg.optional = nil
+	esg.seqs = nil
+	esg.statusCount = 0
+	esg.warnings = nil
+}
+
+// resetLocked clears and reopens this error status group instance. The caller must hold esg.mutex.
+func (esg *ErrorStatusGroup) resetLocked() {
+	esg.clearLocked()
+	esg.closed = false
+	esg.lateWrites = 0
+	esg.stats = taskStats{}
+	esg.fatal = nil
+	esg.subscribers.dropped = 0
+	esg.tripReason = ""
+}
+
+// record saves entries and groups to this error status group instance, publishes entries to
+// subscribers and then runs the registered hooks once the lock has been released. Warnings and
+// optional entries are saved apart from the other entries and neither trip the group nor run the
+// hooks. seq is the submission index of the task that produced entries, or 0 if entries were added
+// directly.
+func (esg *ErrorStatusGroup) record(seq uint64, entries []Entry, groups []namedErrorStatusGroup) {
+	esg.mutex.Lock()
+
+	if esg.closed {
+		defer esg.mutex.Unlock()
+		esg.lateWriteLocked()
+		return
+	}
+
+	for _, entry := range entries {
+		if esg.storeLocked(entry) && esg.config.order.kind == orderSubmission {
+			esg.seqs = append(esg.seqs, esg.nextSeqLocked(seq))
+		}
+
+		esg.subscribers.publish(entry)
+	}
+
+	esg.groups = append(esg.groups, groups...)
+
+	trip := esg.checkThresholdsLocked(entries)
+	registered := esg.hooks.snapshot()
+	esg.mutex.Unlock()
+
+	if trip != nil {
+		trip()
+	}
+
+	registered.runEntries(entries)
+}
+
+// checkThresholdsLocked trips this error status group instance if it crossed one of its thresholds
+// after entries were added. It returns the function cancelling the group's context that the caller
+// must call once it has released esg.mutex, or nil if there is nothing to cancel.
+func (esg *ErrorStatusGroup) checkThresholdsLocked(entries []Entry) func() {
+	if esg.tripReason != "" {
 		return nil
 	}
-	return errors.New(errMessage)
+
+	reason := ""
+	for _, entry := range entries {
+		if entry.Severity == SeverityFatal {
+			esg.fatal = entry.Err
+			reason = fatalReason(entry.Err)
+		} else if entry.HasStatus && entry.Severity != SeverityWarning && !entry.Optional {
+			reason = esg.config.statusExceeded(entry.Status)
+		}
+
+		if reason != "" {
+			break
+		}
+	}
+
+	if reason == "" {
+		reason = esg.config.errorsExceeded(esg.errorCount, len(esg.entries))
+	}
+
+	return esg.tripLocked(reason)
+}
+
+// tripLocked trips this error status group instance for reason unless reason is empty. It returns
+// the function cancelling the group's context that the caller must call once it has released
+// esg.mutex, or nil if there is nothing to cancel.
+func (esg *ErrorStatusGroup) tripLocked(reason string) func() {
+	if reason == "" {
+		return nil
+	}
+
+	esg.tripReason = reason
+
+	if esg.cancel == nil {
+		return nil
+	}
+
+	cancel := esg.cancel
+	cause := tripCause(esg.fatal, reason)
+
+	return func() {
+		cancel(cause)
+	}
+}
+
+// nextSeqLocked returns seq if it is a submission index or else allocates a new one. The caller
+// must hold esg.mutex.
+func (esg *ErrorStatusGroup) nextSeqLocked(seq uint64) uint64 {
+	if seq != 0 {
+		return seq
+	}
+
+	esg.submitted++
+
+	return esg.submitted
+}
+
+// lateWriteLocked records a value added after Close. The caller must hold esg.mutex.
+func (esg *ErrorStatusGroup) lateWriteLocked() {
+	esg.lateWrites++
+
+	if esg.config.panicOnLateWrite {
+		panic(ErrGroupClosed)
+	}
+}
+
+// contains reports whether target is this error status group instance or one of its descendants.
+func (esg *ErrorStatusGroup) contains(target *ErrorStatusGroup) bool {
+	if esg == target {
+		return true
+	}
+
+	esg.mutex.Lock()
+	groups := esg.groups[:len(esg.groups):len(esg.groups)]
+	esg.mutex.Unlock()
+
+	for _, child := range groups {
+		if child.group.contains(target) {
+			return true
+		}
+	}
+
+	return false
 }
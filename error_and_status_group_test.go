@@ -91,6 +91,46 @@ func TestErrorStatusGroup_AddError(t *testing.T) {
 	})
 }
 
+func TestErrorStatusGroup_AddGroup(t *testing.T) {
+	admins := NewErrorStatusGroup()
+	admins.AddStatusAndError(503, errors.New("admins unavailable"))
+
+	users := NewErrorStatusGroup()
+	users.AddStatusAndError(404, errors.New("users not found"))
+
+	esg := NewErrorStatusGroup()
+	esg.AddStatus(100)
+	esg.AddGroup("admins", admins)
+	esg.AddGroup("users", users)
+
+	t.Run("verify HighestStatus() rolls up through child groups", func(t *testing.T) {
+		assert.Equal(t, 503, esg.HighestStatus())
+	})
+	t.Run("verify LowestStatus() rolls up through child groups", func(t *testing.T) {
+		assert.Equal(t, 100, esg.LowestStatus())
+	})
+	t.Run("verify Error() renders child groups as an indented tree", func(t *testing.T) {
+		expected := strings.Join([]string{
+			"lowest status: [100]",
+			"highest status: [503]",
+			"status counts: [100: 1, 404: 1, 503: 1]",
+			"status classes: [1xx: 1, 4xx: 1, 5xx: 1]",
+			"  admins: admins unavailable",
+			"  users: users not found",
+		}, "\n")
+		assert.Equal(t, expected, esg.Error())
+	})
+	t.Run("verify ToStatusAndError() reports the rolled up status", func(t *testing.T) {
+		status, err := esg.ToStatusAndError()
+		assert.Equal(t, 503, status)
+		assert.NotNil(t, err)
+	})
+	t.Run("verify LenErrors() only counts errors added directly", func(t *testing.T) {
+		assert.Equal(t, 0, esg.LenErrors())
+		assert.Equal(t, 2, len(esg.Unwrap()))
+	})
+}
+
 func TestErrorStatusGroup_AddStatus(t *testing.T) {
 	esg := NewErrorStatusGroup()
 
@@ -178,6 +218,80 @@ func TestErrorStatusGroup_All(t *testing.T) {
 	})
 }
 
+func TestErrorStatusGroup_Close(t *testing.T) {
+	esg := NewErrorStatusGroup()
+	esg.AddStatusAndError(400, errors.New("first message"))
+	esg.Close()
+
+	esg.AddError(errors.New("late message"))
+	esg.AddStatus(500)
+	esg.AddStatusAndError(500, errors.New("late message"))
+
+	t.Run("verify values added after Close() are discarded", func(t *testing.T) {
+		assert.True(t, esg.Closed())
+		assert.Equal(t, 1, esg.LenErrors())
+		assert.Equal(t, 1, esg.LenStatuses())
+		assert.Equal(t, 400, esg.HighestStatus())
+	})
+	t.Run("verify values added after Close() are counted by LateWrites()", func(t *testing.T) {
+		assert.Equal(t, 3, esg.LateWrites())
+	})
+	t.Run("verify Drain() does not reopen a closed group", func(t *testing.T) {
+		esg.Drain()
+		esg.AddStatus(500)
+		assert.True(t, esg.Closed())
+		assert.Equal(t, 0, esg.LenStatuses())
+		assert.Equal(t, 4, esg.LateWrites())
+	})
+	t.Run("verify PanicOnLateWrite() panics on values added after Close()", func(t *testing.T) {
+		other := NewErrorStatusGroup(PanicOnLateWrite())
+		other.Close()
+
+		defer func() {
+			assert.Equal(t, ErrGroupClosed, recover())
+		}()
+
+		other.AddStatus(500)
+	})
+}
+
+func TestErrorStatusGroup_Drain(t *testing.T) {
+	esg := NewErrorStatusGroup()
+	esg.AddStatusAndError(100, errors.New("first message"))
+	esg.AddStatusAndError(500, errors.New("last message"))
+
+	snapshot := esg.Drain()
+
+	t.Run("verify Drain() returns every value saved to the group", func(t *testing.T) {
+		assert.Equal(t, 2, snapshot.LenErrors())
+		assert.Equal(t, 2, snapshot.LenStatuses())
+		assert.Equal(t, 100, snapshot.LowestStatus())
+		assert.Equal(t, 500, snapshot.HighestStatus())
+	})
+	t.Run("verify Drain() clears the group including the lowest and highest status values", func(t *testing.T) {
+		assert.Equal(t, 0, esg.LenErrors())
+		assert.Equal(t, 0, esg.LenStatuses())
+		assert.Equal(t, 200, esg.LowestStatus())
+		assert.Equal(t, 200, esg.HighestStatus())
+	})
+	t.Run("verify Drain() hands the tripped state over to the snapshot", func(t *testing.T) {
+		tripped := NewErrorStatusGroup()
+		tripped.AddFatal(errors.New("corrupt index"))
+
+		snapshot := tripped.Drain()
+		assert.True(t, snapshot.Tripped())
+		assert.Equal(t, "fatal error: corrupt index", snapshot.ToError().Error())
+
+		assert.False(t, tripped.Tripped())
+		assert.Nil(t, tripped.ToError())
+		assert.True(t, tripped.Go(func() (int, error) { return 200, nil }))
+
+		status, err := tripped.Wait()
+		assert.Equal(t, 200, status)
+		assert.Nil(t, err)
+	})
+}
+
 func TestErrorStatusGroup_Error(t *testing.T) {
 	firstMessage := "first message"
 	lastMessage := "last message"
@@ -199,6 +313,10 @@ func TestErrorStatusGroup_Error(t *testing.T) {
 		sb.WriteString("\n")
 		sb.WriteString(fmt.Sprintf("highest status: [%d]", 300))
 		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("status counts: [100: 1, 200: %d, 300: 1]", numToAdd))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("status classes: [1xx: 1, 2xx: %d, 3xx: 1]", numToAdd))
+		sb.WriteString("\n")
 
 		var stringsToConcat []string
 
@@ -340,6 +458,47 @@ func TestErrorStatusGroup_LowestStatus(t *testing.T) {
 	})
 }
 
+func TestErrorStatusGroup_Merge(t *testing.T) {
+	first := NewErrorStatusGroup()
+	first.AddStatusAndError(400, errors.New("first message"))
+
+	second := NewErrorStatusGroup()
+	second.AddStatusAndError(502, errors.New("second message"))
+	second.AddStatus(100)
+
+	first.Merge(second)
+
+	t.Run("verify Merge() adds the errors and statuses of the other group", func(t *testing.T) {
+		assert.Equal(t, 2, first.LenErrors())
+		assert.Equal(t, 3, first.LenStatuses())
+	})
+	t.Run("verify Merge() updates the lowest and highest status values", func(t *testing.T) {
+		assert.Equal(t, 100, first.LowestStatus())
+		assert.Equal(t, 502, first.HighestStatus())
+	})
+	t.Run("verify Merge() does not modify the other group", func(t *testing.T) {
+		assert.Equal(t, 1, second.LenErrors())
+		assert.Equal(t, 2, second.LenStatuses())
+	})
+}
+
+func TestErrorStatusGroup_Reset(t *testing.T) {
+	esg := NewErrorStatusGroup()
+	esg.AddStatusAndError(100, errors.New("first message"))
+	esg.AddStatusAndError(500, errors.New("last message"))
+	esg.Reset()
+
+	t.Run("verify Reset() removes every error and status", func(t *testing.T) {
+		assert.Equal(t, 0, esg.LenErrors())
+		assert.Equal(t, 0, esg.LenStatuses())
+		assert.Nil(t, esg.ToError())
+	})
+	t.Run("verify Reset() restores the lowest and highest status values", func(t *testing.T) {
+		assert.Equal(t, 200, esg.LowestStatus())
+		assert.Equal(t, 200, esg.HighestStatus())
+	})
+}
+
 func TestErrorStatusGroup_ToStatusAndError(t *testing.T) {
 	firstMessage := "first message"
 	lastMessage := "last message"
@@ -361,6 +520,10 @@ func TestErrorStatusGroup_ToStatusAndError(t *testing.T) {
 		sb.WriteString("\n")
 		sb.WriteString(fmt.Sprintf("highest status: [%d]", 300))
 		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("status counts: [100: 1, 200: %d, 300: 1]", numToAdd))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("status classes: [1xx: 1, 2xx: %d, 3xx: 1]", numToAdd))
+		sb.WriteString("\n")
 
 		var stringsToConcat []string
 
@@ -402,6 +565,10 @@ func TestErrorStatusGroup_ToError(t *testing.T) {
 		sb.WriteString("\n")
 		sb.WriteString(fmt.Sprintf("highest status: [%d]", 300))
 		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("status counts: [100: 1, 200: %d, 300: 1]", numToAdd))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("status classes: [1xx: 1, 2xx: %d, 3xx: 1]", numToAdd))
+		sb.WriteString("\n")
 
 		var stringsToConcat []string
 
@@ -440,3 +607,80 @@ func GenerateRandomNumber() int {
 	byteToInt, _ := strconv.Atoi(string(ret))
 	return byteToInt
 }
+
+func BenchmarkErrorStatusGroup_AddStatusAndError(b *testing.B) {
+	esg := NewErrorStatusGroup()
+	err := errors.New("benchmark error")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		esg.AddStatusAndError(500, err)
+	}
+}
+
+func BenchmarkErrorStatusGroup_AddStatusAndErrorParallel(b *testing.B) {
+	esg := NewErrorStatusGroup()
+	err := errors.New("benchmark error")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			esg.AddStatusAndError(500, err)
+		}
+	})
+}
+
+func BenchmarkErrorStatusGroup_All(b *testing.B) {
+	esg := NewErrorStatusGroup()
+	for i := 0; i < 100; i++ {
+		esg.AddStatusAndError(500, errors.New("benchmark error"))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		esg.All()
+	}
+}
+
+func BenchmarkErrorStatusGroup_ToStatusAndError(b *testing.B) {
+	esg := NewErrorStatusGroup()
+	for i := 0; i < 10; i++ {
+		esg.AddStatusAndError(500, errors.New("benchmark error"))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		esg.ToStatusAndError()
+	}
+}
+
+// searchHandler holds an error status group by value, the way a request handler aggregating the
+// results of several backends would.
+type searchHandler struct {
+	statuses ErrorStatusGroup
+}
+
+func TestErrorStatusGroup_Embedded(t *testing.T) {
+	handler := &searchHandler{}
+
+	for i := 0; i < 10; i++ {
+		handler.statuses.Go(func() (int, error) {
+			return GenerateRandomNumber(), nil
+		})
+	}
+
+	t.Run("verify a zero value field collects concurrently added statuses", func(t *testing.T) {
+		handler.statuses.Wait()
+
+		assert.Equal(t, 10, handler.statuses.LenStatuses())
+		assert.Equal(t, 0, handler.statuses.LenErrors())
+	})
+}
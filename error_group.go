@@ -1,40 +1,94 @@
 package error_group
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"sync"
 )
 
-type errorGroup struct {
-	mutex  *sync.Mutex
-	errors []error
+// ErrGroupClosed is the value a group created with PanicOnLateWrite panics with when a value is
+// added to it after Close has been called.
+var ErrGroupClosed = errors.New("error_group: value added to a closed group")
+
+// ErrorGroup collects errors from any number of goroutines. It guards its errors with a single
+// mutex so that every method observes them in one consistent state. The zero value is an empty
+// error group instance without any options, ready to use. An ErrorGroup must not be copied after
+// first use.
+type ErrorGroup struct {
+	cancel      context.CancelCauseFunc
+	closed      bool
+	config      config
+	ctx         context.Context
+	errors      []error
+	fatal       error
+	groups      []namedErrorGroup
+	hooks       hooks
+	lateWrites  int
+	mutex       sync.Mutex
+	running     int
+	seqs        []uint64
+	stats       taskStats
+	submitted   uint64
+	subscribers subscribers
+	successes   int
+	tasks       sync.WaitGroup
+	tripReason  string
+	warnings    []error
 }
 
-//goland:noinspection GoExportedFuncWithUnexportedType
-func NewErrorGroup() *errorGroup {
-	errorMutex := sync.Mutex{}
+// namedErrorGroup is a child error group added to a parent with AddGroup.
+type namedErrorGroup struct {
+	group *ErrorGroup
+	name  string
+}
 
-	return &errorGroup{
-		mutex: &errorMutex,
+// NewErrorGroup returns a new error group instance configured with opts.
+func NewErrorGroup(opts ...Option) *ErrorGroup {
+	eg := &ErrorGroup{
+		config: newConfig(opts),
 	}
+
+	if eg.config.registry != nil {
+		eg.config.registry.add(eg, eg.config.name)
+	}
+
+	return eg
 }
 
 // Add adds an error to this error group instance.
-func (eg *errorGroup) Add(err error) {
+func (eg *ErrorGroup) Add(err error) {
 	if err == nil {
 		return
 	}
 
-	eg.mutex.Lock()
-	defer eg.mutex.Unlock()
+	eg.record(SeverityError, 0, []error{err}, nil)
+}
 
-	eg.errors = append(eg.errors, err)
-	return
+// AddGroup adds child as a named child of this error group instance. Errors added to child,
+// before or after this call, are rendered by Error as "name: message" lines indented beneath
+// the errors of this group and are reachable through Unwrap. Entry accessors such as Len,
+// All, First and Last only consider the errors added to this group directly. AddGroup
+// panics if adding child would create a cycle.
+func (eg *ErrorGroup) AddGroup(name string, child *ErrorGroup) {
+	if child == nil {
+		return
+	}
+
+	if child.contains(eg) {
+		panic("error_group: AddGroup would create a cycle")
+	}
+
+	eg.record(SeverityError, 0, nil, []namedErrorGroup{{group: child, name: name}})
 }
 
-// All returns a new slice containing every error in this error group instance.
-func (eg *errorGroup) All() []error {
+// All returns a new slice containing every error in this error group instance, in the ordering the
+// group was created with.
+func (eg *ErrorGroup) All() []error {
+	if eg.config.order.kind != orderInsertion {
+		return eg.Snapshot().All()
+	}
+
 	eg.mutex.Lock()
 	defer eg.mutex.Unlock()
 
@@ -45,29 +99,73 @@ func (eg *errorGroup) All() []error {
 	return duplicate
 }
 
-// Error fulfills the builtin.Error interface and returns a concatenated string of all the errors in this error group instance.
-func (eg *errorGroup) Error() string {
+// Close seals this error group instance. Values added after Close are discarded and counted by
+// LateWrites, or cause a panic if the group was created with PanicOnLateWrite. Close should be
+// called once the group has been read for the last time, e.g. right before returning ToError.
+// Closing a group also closes every channel returned by Subscribe and removes the group from the
+// registry it was created with.
+func (eg *ErrorGroup) Close() {
 	eg.mutex.Lock()
-	defer eg.mutex.Unlock()
+	eg.closed = true
+	eg.subscribers.close()
+	eg.mutex.Unlock()
 
-	if len(eg.errors) == 0 {
-		return ""
+	if eg.config.registry != nil {
+		eg.config.registry.remove(eg)
 	}
+}
 
-	sb := strings.Builder{}
+// Closed reports whether Close has been called on this error group instance.
+func (eg *ErrorGroup) Closed() bool {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
 
-	for _, currentError := range eg.errors {
-		sb.WriteString(currentError.Error())
-		sb.WriteString("\n")
+	return eg.closed
+}
+
+// Drain atomically captures the current state of this error group instance and clears it. Errors
+// added concurrently are either part of the returned snapshot or remain in the group afterwards,
+// they are never lost. Child groups are detached from this group and captured in the snapshot. The
+// tripped state is reported by the snapshot and cleared from the group, so a group that tripped
+// accepts tasks again, but a context returned by NewErrorGroupWithContext stays cancelled.
+func (eg *ErrorGroup) Drain() Snapshot {
+	eg.mutex.Lock()
+	drained := &ErrorGroup{
+		config:     eg.config,
+		errors:     eg.errors,
+		fatal:      eg.fatal,
+		groups:     eg.groups,
+		seqs:       eg.seqs,
+		tripReason: eg.tripReason,
+		warnings:   eg.warnings,
 	}
+	eg.clearLocked()
+	eg.fatal = nil
+	eg.tripReason = ""
+	eg.mutex.Unlock()
 
-	return strings.TrimSuffix(sb.String(), "\n")
+	return drained.Snapshot()
+}
+
+// Dropped returns the number of times an error could not be delivered to a channel returned by
+// Subscribe because its buffer was full.
+func (eg *ErrorGroup) Dropped() int {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	return eg.subscribers.dropped
+}
+
+// Error fulfills the builtin.Error interface and returns a concatenated string of all the errors in this
+// error group instance followed by the errors of any child groups.
+func (eg *ErrorGroup) Error() string {
+	return eg.Snapshot().Error()
 }
 
 // First returns the first error saved to this error group instance. Since this
 // library is thread safe - the first error saved is not deterministic if the
 // library is used in a multithreaded environment.
-func (eg *errorGroup) First() error {
+func (eg *ErrorGroup) First() error {
 	eg.mutex.Lock()
 	defer eg.mutex.Unlock()
 
@@ -76,30 +174,294 @@ func (eg *errorGroup) First() error {
 
 // Last returns the (current) last error saved to this error group instance.
 // Subsequent calls to Add can cause the value returned here to no longer be the last.
-func (eg *errorGroup) Last() error {
+func (eg *ErrorGroup) Last() error {
 	eg.mutex.Lock()
 	defer eg.mutex.Unlock()
 
 	return eg.errors[len(eg.errors)-1]
 }
 
+// LateWrites returns the number of values that were discarded because they were added to this
+// error group instance after Close had been called.
+func (eg *ErrorGroup) LateWrites() int {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	return eg.lateWrites
+}
+
 // Len returns the (current) length or number of errors saved to this error instance.
 // Subsequent calls to Add can cause the value returned here to no longer be accurate.
-func (eg *errorGroup) Len() int {
+func (eg *ErrorGroup) Len() int {
 	eg.mutex.Lock()
 	defer eg.mutex.Unlock()
 
 	return len(eg.errors)
 }
 
+// Merge adds every error, warning and child group currently saved to other to this error group
+// instance. Values added to other after Merge returns are not reflected in this group.
+func (eg *ErrorGroup) Merge(other *ErrorGroup) {
+	if other == nil {
+		return
+	}
+
+	other.mutex.Lock()
+	errs := other.errors[:len(other.errors):len(other.errors)]
+	groups := other.groups[:len(other.groups):len(other.groups)]
+	warnings := other.warnings[:len(other.warnings):len(other.warnings)]
+	other.mutex.Unlock()
+
+	for _, child := range groups {
+		if child.group.contains(eg) {
+			panic("error_group: Merge would create a cycle")
+		}
+	}
+
+	eg.record(SeverityError, 0, errs, groups)
+
+	if len(warnings) > 0 {
+		eg.record(SeverityWarning, 0, warnings, nil)
+	}
+}
+
+// OnError registers hook to be called with every error added to this error group instance from
+// now on. Hooks are called synchronously, in registration order, by the goroutine that added the
+// error once the group's lock has been released, so a slow hook slows down that goroutine but
+// may safely call back into the group.
+func (eg *ErrorGroup) OnError(hook func(error)) {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	eg.hooks.errorHooks = append(eg.hooks.errorHooks, hook)
+}
+
+// Reset removes every error, warning and child group from this error group instance, clears its
+// tripped state and reopens it if it was closed so that it can be reused, adding it back to the
+// registry it was created with. A context returned by NewErrorGroupWithContext that has already
+// been cancelled stays cancelled.
+func (eg *ErrorGroup) Reset() {
+	eg.mutex.Lock()
+	eg.resetLocked()
+	eg.mutex.Unlock()
+
+	if eg.config.registry != nil {
+		eg.config.registry.add(eg, eg.config.name)
+	}
+}
+
+// Subscribe returns a channel that receives an Entry for every error added to this error group
+// instance from now on. Delivery never blocks the goroutine adding the error: entries are sent in
+// the order they were added until the channel's buffer is full, after which they are discarded
+// and counted by Dropped. The channel is closed by Close. Use OnError instead when every error must
+// be observed.
+func (eg *ErrorGroup) Subscribe() <-chan Entry {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	if eg.closed {
+		return closedSubscription()
+	}
+
+	return eg.subscribers.add()
+}
+
 // ToError is a convenience function that converts the errors contained in this
 // error group into one single error. This is useful for returning the ErrorGroup
 // object instance as a single generic builtin.Error interface instance.
-func (eg *errorGroup) ToError() error {
-	errMessage := eg.Error()
-	if errMessage == "" {
+//
+// Once the group has tripped one of its thresholds ToError returns a *ThresholdExceededError
+// wrapping the collected errors instead, or a *FatalError if it was tripped by AddFatal. The first
+// call finalizes the group, see Observe.
+func (eg *ErrorGroup) ToError() error {
+	snapshot := eg.Snapshot()
+	eg.finalize(snapshot)
+
+	return snapshot.ToError()
+}
+
+// Tripped reports whether this error group instance has crossed one of the thresholds configured
+// with MaxErrors or MaxErrorRate or had an error added with AddFatal.
+func (eg *ErrorGroup) Tripped() bool {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	return eg.tripReason != ""
+}
+
+// Unwrap returns the errors saved to this error group instance followed by one error for
+// each child group that holds errors. It allows errors.Is and errors.As to traverse the whole
+// hierarchy of groups.
+func (eg *ErrorGroup) Unwrap() []error {
+	return eg.Snapshot().Unwrap()
+}
+
+// clearLocked removes every error, warning and child group from this error group instance.
+// Snapshots share the backing arrays of the slices so they are replaced rather than truncated. The
+// caller must hold eg.mutex.
+func (eg *ErrorGroup) clearLocked() {
+	eg.errors = nil
+	eg.groups = nil
+	eg.seqs = nil
+	eg.successes = 0
+	eg.warnings = nil
+}
+
+// resetLocked clears and reopens this error group instance. The caller must hold eg.mutex.
+func (eg *ErrorGroup) resetLocked() {
+	eg.clearLocked()
+	eg.closed = false
+	eg.lateWrites = 0
+	eg.stats = taskStats{}
+	eg.fatal = nil
+	eg.subscribers.dropped = 0
+	eg.tripReason = ""
+}
+
+// record saves errs with the given severity and groups to this error group instance, publishes
+// errs to subscribers and then runs the registered hooks once the lock has been released. Warnings
+// are saved apart from errors and neither trip the group nor run the hooks. seq is the submission
+// index of the task that produced errs, or 0 if errs were added directly.
+func (eg *ErrorGroup) record(severity Severity, seq uint64, errs []error, groups []namedErrorGroup) {
+	eg.mutex.Lock()
+
+	if eg.closed {
+		defer eg.mutex.Unlock()
+		eg.lateWriteLocked()
+		return
+	}
+
+	for _, err := range errs {
+		eg.subscribers.publish(Entry{Err: err, Severity: severity})
+	}
+
+	if severity == SeverityWarning {
+		defer eg.mutex.Unlock()
+		eg.warnings = append(eg.warnings, errs...)
+		return
+	}
+
+	eg.errors = append(eg.errors, errs...)
+	eg.groups = append(eg.groups, groups...)
+
+	if eg.config.order.kind == orderSubmission {
+		for range errs {
+			eg.seqs = append(eg.seqs, eg.nextSeqLocked(seq))
+		}
+	}
+
+	var trip func()
+	if severity == SeverityFatal && eg.tripReason == "" {
+		eg.fatal = errs[0]
+		trip = eg.tripLocked(fatalReason(errs[0]))
+	} else {
+		trip = eg.checkThresholdsLocked()
+	}
+
+	registered := eg.hooks.snapshot()
+	eg.mutex.Unlock()
+
+	if trip != nil {
+		trip()
+	}
+
+	registered.runErrors(errs)
+}
+
+// checkThresholdsLocked trips this error group instance if it crossed one of its thresholds. It
+// returns the function cancelling the group's context that the caller must call once it has
+// released eg.mutex, or nil if there is nothing to cancel.
+func (eg *ErrorGroup) checkThresholdsLocked() func() {
+	if eg.tripReason != "" {
+		return nil
+	}
+
+	return eg.tripLocked(eg.config.errorsExceeded(len(eg.errors), len(eg.errors)+eg.successes))
+}
+
+// tripLocked trips this error group instance for reason unless reason is empty. It returns the
+// function cancelling the group's context that the caller must call once it has released
+// eg.mutex, or nil if there is nothing to cancel.
+func (eg *ErrorGroup) tripLocked(reason string) func() {
+	if reason == "" {
 		return nil
 	}
 
-	return errors.New(errMessage)
+	eg.tripReason = reason
+
+	if eg.cancel == nil {
+		return nil
+	}
+
+	cancel := eg.cancel
+	cause := tripCause(eg.fatal, reason)
+
+	return func() {
+		cancel(cause)
+	}
+}
+
+// nextSeqLocked returns seq if it is a submission index or else allocates a new one. The caller
+// must hold eg.mutex.
+func (eg *ErrorGroup) nextSeqLocked(seq uint64) uint64 {
+	if seq != 0 {
+		return seq
+	}
+
+	eg.submitted++
+
+	return eg.submitted
+}
+
+// lateWriteLocked records a value added after Close. The caller must hold eg.mutex.
+func (eg *ErrorGroup) lateWriteLocked() {
+	eg.lateWrites++
+
+	if eg.config.panicOnLateWrite {
+		panic(ErrGroupClosed)
+	}
+}
+
+// contains reports whether target is this error group instance or one of its descendants.
+func (eg *ErrorGroup) contains(target *ErrorGroup) bool {
+	if eg == target {
+		return true
+	}
+
+	eg.mutex.Lock()
+	groups := eg.groups[:len(eg.groups):len(eg.groups)]
+	eg.mutex.Unlock()
+
+	for _, child := range groups {
+		if child.group.contains(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeError writes err to sb as a single line indented two spaces per level of depth and
+// prefixed with path when it is not empty.
+func writeError(sb *strings.Builder, depth int, path string, err error) {
+	for i := 0; i < depth; i++ {
+		sb.WriteString("  ")
+	}
+
+	if path != "" {
+		sb.WriteString(path)
+		sb.WriteString(": ")
+	}
+
+	sb.WriteString(err.Error())
+	sb.WriteString("\n")
+}
+
+// joinPath returns the path of a child group named name beneath the group at path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "/" + name
 }
@@ -36,6 +36,56 @@ func TestErrorGroup_Add(t *testing.T) {
 	})
 }
 
+func TestErrorGroup_AddGroup(t *testing.T) {
+	sentinel := errors.New("users unavailable")
+
+	admins := NewErrorGroup()
+	admins.Add(errors.New("admins timed out"))
+
+	users := NewErrorGroup()
+	users.Add(sentinel)
+
+	search := NewErrorGroup()
+	search.AddGroup("admins", admins)
+	search.AddGroup("users", users)
+
+	eg := NewErrorGroup()
+	eg.Add(errors.New("request failed"))
+	eg.AddGroup("search", search)
+
+	t.Run("verify Error() renders child groups as an indented tree", func(t *testing.T) {
+		expected := strings.Join([]string{
+			"request failed",
+			"    search/admins: admins timed out",
+			"    search/users: users unavailable",
+		}, "\n")
+		assert.Equal(t, expected, eg.Error())
+	})
+	t.Run("verify Len() only counts errors added directly", func(t *testing.T) {
+		assert.Equal(t, 1, eg.Len())
+	})
+	t.Run("verify errors.Is() traverses the whole hierarchy", func(t *testing.T) {
+		assert.True(t, errors.Is(eg, sentinel))
+	})
+	t.Run("verify errors added to a child after AddGroup() are reflected", func(t *testing.T) {
+		search.Add(errors.New("search degraded"))
+		assert.True(t, strings.Contains(eg.Error(), "\n  search: search degraded"))
+	})
+	t.Run("verify AddGroup() panics when it would create a cycle", func(t *testing.T) {
+		defer func() {
+			assert.NotNil(t, recover())
+		}()
+
+		admins.AddGroup("root", eg)
+	})
+	t.Run("verify Error() returns the empty string when no group holds errors", func(t *testing.T) {
+		other := NewErrorGroup()
+		other.AddGroup("empty", NewErrorGroup())
+		assert.Equal(t, "", other.Error())
+		assert.Nil(t, other.ToError())
+	})
+}
+
 func TestErrorGroup_All(t *testing.T) {
 	firstMessage := "first message"
 	lastMessage := "last message"
@@ -71,6 +121,99 @@ func TestErrorGroup_All(t *testing.T) {
 	})
 }
 
+func TestErrorGroup_Close(t *testing.T) {
+	eg := NewErrorGroup()
+	eg.Add(errors.New("first message"))
+	eg.Close()
+
+	eg.Add(errors.New("late message"))
+	eg.Merge(eg)
+	eg.AddGroup("late", NewErrorGroup())
+
+	t.Run("verify values added after Close() are discarded", func(t *testing.T) {
+		assert.True(t, eg.Closed())
+		assert.Equal(t, "first message", eg.Error())
+	})
+	t.Run("verify values added after Close() are counted by LateWrites()", func(t *testing.T) {
+		assert.Equal(t, 3, eg.LateWrites())
+	})
+	t.Run("verify Reset() reopens a closed group", func(t *testing.T) {
+		eg.Reset()
+		eg.Add(errors.New("second message"))
+		assert.False(t, eg.Closed())
+		assert.Equal(t, 0, eg.LateWrites())
+		assert.Equal(t, 1, eg.Len())
+	})
+	t.Run("verify PanicOnLateWrite() panics on values added after Close()", func(t *testing.T) {
+		other := NewErrorGroup(PanicOnLateWrite())
+		other.Close()
+
+		defer func() {
+			assert.Equal(t, ErrGroupClosed, recover())
+			assert.Equal(t, 1, other.LateWrites())
+		}()
+
+		other.Add(errors.New("late message"))
+	})
+}
+
+func TestErrorGroup_Drain(t *testing.T) {
+	eg := NewErrorGroup()
+
+	var wg sync.WaitGroup
+	numToAdd := 100000
+	maxRoutines := 1000
+	guard := make(chan struct{}, maxRoutines)
+
+	drained := 0
+	for i := 0; i < numToAdd; i++ {
+		guard <- struct{}{}
+		wg.Add(1)
+		go func() {
+			<-guard
+			eg.Add(errors.New(generateRandomString(10)))
+			wg.Done()
+		}()
+
+		if i%1000 == 0 {
+			drained += eg.Drain().Len()
+		}
+	}
+
+	wg.Wait()
+	drained += eg.Drain().Len()
+
+	t.Run("verify Drain() never loses errors added concurrently", func(t *testing.T) {
+		assert.Equal(t, numToAdd, drained)
+	})
+	t.Run("verify Drain() leaves the group empty", func(t *testing.T) {
+		assert.Equal(t, 0, eg.Len())
+		assert.Nil(t, eg.ToError())
+	})
+	t.Run("verify Drain() detaches child groups", func(t *testing.T) {
+		child := NewErrorGroup()
+		child.Add(errors.New("child message"))
+		eg.AddGroup("child", child)
+
+		snapshot := eg.Drain()
+		assert.Equal(t, "  child: child message", snapshot.Error())
+		assert.Equal(t, "", eg.Error())
+	})
+	t.Run("verify Drain() hands the tripped state over to the snapshot", func(t *testing.T) {
+		tripped := NewErrorGroup(MaxErrors(0))
+		tripped.Add(errors.New("first message"))
+
+		snapshot := tripped.Drain()
+		assert.True(t, snapshot.Tripped())
+		assert.NotNil(t, snapshot.ToError())
+
+		assert.False(t, tripped.Tripped())
+		assert.Nil(t, tripped.ToError())
+		assert.True(t, tripped.Go(func() error { return nil }))
+		assert.Nil(t, tripped.Wait())
+	})
+}
+
 func TestErrorGroup_Error(t *testing.T) {
 	eg := NewErrorGroup()
 	first := "first message"
@@ -127,6 +270,48 @@ func TestErrorGroup_Len(t *testing.T) {
 	})
 }
 
+func TestErrorGroup_Merge(t *testing.T) {
+	first := NewErrorGroup()
+	first.Add(errors.New("first message"))
+
+	second := NewErrorGroup()
+	second.Add(errors.New("second message"))
+	second.AddGroup("child", NewErrorGroup())
+
+	first.Merge(second)
+
+	t.Run("verify Merge() adds the errors of the other group", func(t *testing.T) {
+		assert.Equal(t, 2, first.Len())
+		assert.Equal(t, "first message\nsecond message", first.Error())
+	})
+	t.Run("verify Merge() does not modify the other group", func(t *testing.T) {
+		assert.Equal(t, 1, second.Len())
+	})
+	t.Run("verify Merge() with itself duplicates its errors", func(t *testing.T) {
+		first.Merge(first)
+		assert.Equal(t, 4, first.Len())
+	})
+}
+
+func TestErrorGroup_Reset(t *testing.T) {
+	eg := NewErrorGroup()
+	eg.Add(errors.New("first message"))
+	eg.AddGroup("child", NewErrorGroup())
+
+	snapshot := eg.Snapshot()
+	eg.Reset()
+
+	t.Run("verify Reset() removes every error", func(t *testing.T) {
+		assert.Equal(t, 0, eg.Len())
+		assert.Equal(t, "", eg.Error())
+	})
+	t.Run("verify Reset() does not affect earlier snapshots", func(t *testing.T) {
+		eg.Add(errors.New("second message"))
+		assert.Equal(t, "first message", snapshot.Error())
+		assert.Equal(t, "second message", eg.Error())
+	})
+}
+
 func TestErrorGroup_ToError(t *testing.T) {
 	eg := NewErrorGroup()
 
@@ -156,3 +341,29 @@ func generateRandomString(n int) string {
 
 	return string(b)
 }
+
+// batchJob embeds an error group, the way a job collecting the failures of its steps would.
+type batchJob struct {
+	ErrorGroup
+	name string
+}
+
+func TestErrorGroup_Embedded(t *testing.T) {
+	job := &batchJob{name: generateRandomString(10)}
+
+	for i := 0; i < 10; i++ {
+		job.Go(func() error {
+			return errors.New(generateRandomString(10))
+		})
+	}
+
+	t.Run("verify an embedded zero value collects concurrently added errors", func(t *testing.T) {
+		assert.NotNil(t, job.Wait())
+		assert.Equal(t, 10, job.Len())
+	})
+	t.Run("verify an embedded group satisfies ErrorCollector through its parent", func(t *testing.T) {
+		var collector ErrorCollector = job
+
+		assert.Equal(t, 10, collector.Len())
+	})
+}
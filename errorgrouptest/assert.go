@@ -0,0 +1,93 @@
+// Package errorgrouptest provides assertions for tests of code that uses error groups and error
+// status groups. Every assertion reports failures with t.Errorf, marks itself as a helper and
+// returns whether it passed.
+package errorgrouptest
+
+import (
+	"errors"
+	"testing"
+)
+
+// AssertContains asserts that errors.Is finds target in g or any of its child groups.
+func AssertContains(t testing.TB, g error, target error) bool {
+	t.Helper()
+
+	if g == nil || !errors.Is(g, target) {
+		t.Errorf("expected group to contain %q\ngroup:\n%s", target, errorString(g))
+		return false
+	}
+
+	return true
+}
+
+// AssertError asserts that the Error string of g equals expected and shows a line by line diff
+// otherwise.
+func AssertError(t testing.TB, g error, expected string) bool {
+	t.Helper()
+
+	if actual := errorString(g); actual != expected {
+		t.Errorf("unexpected error string (-expected +actual):\n%s", Diff(expected, actual))
+		return false
+	}
+
+	return true
+}
+
+// AssertLen asserts that g holds n errors, not counting those of child groups. g must be a group
+// with a LenErrors or a Len method.
+func AssertLen(t testing.TB, g error, n int) bool {
+	t.Helper()
+
+	var actual int
+
+	switch group := g.(type) {
+	case interface{ LenErrors() int }:
+		actual = group.LenErrors()
+	case interface{ Len() int }:
+		actual = group.Len()
+	default:
+		t.Errorf("AssertLen: %T has neither a LenErrors nor a Len method", g)
+		return false
+	}
+
+	if actual != n {
+		t.Errorf("expected group to hold %d errors, it holds %d\ngroup:\n%s", n, actual, errorString(g))
+		return false
+	}
+
+	return true
+}
+
+// AssertNoErrors asserts that neither g nor any of its child groups holds an error.
+func AssertNoErrors(t testing.TB, g error) bool {
+	t.Helper()
+
+	if message := errorString(g); message != "" {
+		t.Errorf("expected group to hold no errors\ngroup:\n%s", message)
+		return false
+	}
+
+	return true
+}
+
+// AssertStatus asserts that the highest status value of g, including that of its child groups,
+// is status.
+func AssertStatus(t testing.TB, g interface{ HighestStatus() int }, status int) bool {
+	t.Helper()
+
+	if actual := g.HighestStatus(); actual != status {
+		t.Errorf("expected group to have a highest status of %d, it has %d", status, actual)
+		return false
+	}
+
+	return true
+}
+
+// errorString returns the Error string of err, or the empty string if err is nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,111 @@
+package errorgrouptest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jgroeneveld/trial/assert"
+	"github.com/seantcanavan/error_group"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingT is a testing.TB that records failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (rt *recordingT) Errorf(format string, args ...any) {
+	rt.failures = append(rt.failures, fmt.Sprintf(format, args...))
+}
+
+func (rt *recordingT) Helper() {}
+
+func TestAssertions(t *testing.T) {
+	errNotFound := errors.New("not found")
+
+	child := error_group.NewErrorStatusGroup()
+	child.AddStatusAndError(503, errors.New("unavailable"))
+
+	esg := error_group.NewErrorStatusGroup()
+	esg.AddStatusAndError(404, fmt.Errorf("teacher: %w", errNotFound))
+	esg.AddGroup("students", child)
+
+	t.Run("verify passing assertions do not report failures", func(t *testing.T) {
+		recorder := &recordingT{TB: t}
+
+		assert.True(t, AssertContains(recorder, esg, errNotFound))
+		assert.True(t, AssertStatus(recorder, esg, 503))
+		assert.True(t, AssertLen(recorder, esg, 1))
+		assert.True(t, AssertNoErrors(recorder, error_group.NewErrorGroup()))
+		assert.True(t, AssertError(recorder, esg, esg.Error()))
+		assert.Equal(t, 0, len(recorder.failures))
+	})
+	t.Run("verify failing assertions report readable failures", func(t *testing.T) {
+		recorder := &recordingT{TB: t}
+
+		assert.False(t, AssertContains(recorder, esg, os.ErrPermission))
+		assert.False(t, AssertStatus(recorder, esg, 200))
+		assert.False(t, AssertLen(recorder, esg, 3))
+		assert.False(t, AssertNoErrors(recorder, esg))
+		assert.False(t, AssertLen(recorder, errNotFound, 1))
+		assert.Equal(t, 5, len(recorder.failures))
+		assert.True(t, strings.HasPrefix(recorder.failures[1], "expected group to have a highest status of 200, it has 503"))
+		assert.Equal(t, "AssertLen: *errors.errorString has neither a LenErrors nor a Len method", recorder.failures[4])
+	})
+	t.Run("verify AssertError() shows a diff", func(t *testing.T) {
+		recorder := &recordingT{TB: t}
+
+		eg := error_group.NewErrorGroup()
+		eg.Add(errors.New("first message"))
+		eg.Add(errors.New("second message"))
+
+		assert.False(t, AssertError(recorder, eg, "first message\nthird message"))
+		assert.Equal(t, "unexpected error string (-expected +actual):\n  first message\n- third message\n+ second message\n", recorder.failures[0])
+	})
+}
+
+func TestAssertGolden(t *testing.T) {
+	esg := error_group.NewErrorStatusGroup()
+	esg.AddStatusAndError(404, errors.New("teacher not found"))
+	esg.AddStatusAndError(503, errors.New("students unavailable"))
+
+	t.Run("verify the output matches the golden file", func(t *testing.T) {
+		AssertGolden(t, esg, "status_group")
+	})
+	t.Run("verify a mismatch shows a diff against the golden file", func(t *testing.T) {
+		recorder := &recordingT{TB: t}
+
+		esg.AddError(errors.New("extra"))
+
+		assert.False(t, AssertGolden(recorder, esg, "status_group"))
+		assert.True(t, strings.HasSuffix(recorder.failures[0], "  students unavailable\n+ extra\n"))
+	})
+	t.Run("verify a missing golden file is reported", func(t *testing.T) {
+		recorder := &recordingT{TB: t}
+
+		assert.False(t, AssertGolden(recorder, esg, "missing"))
+		assert.True(t, strings.Contains(recorder.failures[0], "run the tests with ERRORGROUPTEST_UPDATE=1"))
+	})
+	t.Run("verify UpdateEnv rewrites the golden file", func(t *testing.T) {
+		path := filepath.Join("testdata", "updated.golden")
+		t.Cleanup(func() {
+			os.Remove(path)
+		})
+		t.Setenv(UpdateEnv, "1")
+
+		assert.True(t, AssertGolden(t, esg, "updated"))
+
+		written, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, esg.Error(), string(written))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("verify Diff() marks removed, added and common lines", func(t *testing.T) {
+		assert.Equal(t, "- a\n  b\n+ c\n", Diff("a\nb", "b\nc"))
+	})
+}
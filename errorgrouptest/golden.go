@@ -0,0 +1,95 @@
+package errorgrouptest
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden rewrite golden files instead of
+// comparing against them. Run "ERRORGROUPTEST_UPDATE=1 go test ./..." after an intended change to
+// the output of a group. An environment variable is used rather than a flag so that test binaries
+// remain free to define their own -update flag.
+const UpdateEnv = "ERRORGROUPTEST_UPDATE"
+
+// AssertGolden asserts that the Error string of g equals the content of testdata/name.golden and
+// shows a line by line diff otherwise. When UpdateEnv is set to a true value such as 1 the golden
+// file is written instead.
+func AssertGolden(t testing.TB, g error, name string) bool {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	actual := errorString(g)
+
+	if update, _ := strconv.ParseBool(os.Getenv(UpdateEnv)); update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("creating the golden file directory: %v", err)
+			return false
+		}
+
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Errorf("writing golden file %s: %v", path, err)
+			return false
+		}
+
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading golden file %s: %v (run the tests with %s=1 to create it)", path, err, UpdateEnv)
+		return false
+	}
+
+	if string(expected) != actual {
+		t.Errorf("output does not match golden file %s (-expected +actual):\n%s", path, Diff(string(expected), actual))
+		return false
+	}
+
+	return true
+}
+
+// Diff returns a line by line diff of expected and actual. Lines only in expected are prefixed
+// with "- ", lines only in actual with "+ " and common lines with two spaces.
+func Diff(expected, actual string) string {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	sb := strings.Builder{}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+
+	return sb.String()
+}
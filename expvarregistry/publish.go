@@ -0,0 +1,20 @@
+// Package expvarregistry publishes the status of the groups in an error_group.Registry through the
+// expvar package. It is kept apart from error_group because importing expvar registers the
+// /debug/vars handler, which exposes the command line and memory statistics of the process, on
+// http.DefaultServeMux:
+//
+//	expvarregistry.Publish("error_groups", error_group.DefaultRegistry)
+package expvarregistry
+
+import (
+	"expvar"
+	"github.com/seantcanavan/error_group"
+)
+
+// Publish exposes the status of every group in registry as the expvar variable called name. Like
+// expvar.Publish it panics if a variable with that name already exists.
+func Publish(name string, registry *error_group.Registry) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return registry.Statuses()
+	}))
+}
@@ -0,0 +1,34 @@
+package expvarregistry
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"github.com/jgroeneveld/trial/assert"
+	"github.com/seantcanavan/error_group"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPublish(t *testing.T) {
+	registry := error_group.NewRegistry()
+
+	eg := error_group.NewErrorGroup(error_group.Register(registry, "batch"))
+	eg.Add(errors.New("unavailable"))
+
+	esg := error_group.NewErrorStatusGroup(error_group.Register(registry, "search"))
+	esg.AddStatus(200)
+
+	// expvar names are global to the process, so each run of the test publishes its own.
+	name := "error_groups_test_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	Publish(name, registry)
+
+	t.Run("verify Publish() exposes the statuses through expvar", func(t *testing.T) {
+		var statuses []error_group.GroupStatus
+		assert.Nil(t, json.Unmarshal([]byte(expvar.Get(name).String()), &statuses))
+		assert.Equal(t, 2, len(statuses))
+		assert.Equal(t, "batch", statuses[0].Name)
+		assert.Equal(t, 1, statuses[0].Errors)
+	})
+}
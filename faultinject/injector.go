@@ -0,0 +1,232 @@
+// Package faultinject injects errors, statuses, delays and panics into the tasks started by error
+// groups and error status groups so that tests can exercise partial failures.
+//
+// Faults are configured per named task, either with a probability drawn from a seeded random
+// number generator, which makes runs reproducible, or as a scripted schedule:
+//
+//	injector := faultinject.New(42)
+//	injector.Probability("learner.Search", 0.5, faultinject.Fault{Status: 503, Err: errUnavailable})
+//	injector.Script("teacher.Search", nil, &faultinject.Fault{Delay: time.Second})
+//
+//	esg.Go(injector.Wrap("learner.Search", searchLearners))
+package faultinject
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// AnyTask configures the faults of every task that has no faults of its own. Each of those tasks
+// still counts its calls and draws its random decisions on its own.
+const AnyTask = "*"
+
+// Fault describes what is injected into a task. The task is delayed by Delay first. It then
+// panics with Panic if it is not nil. Otherwise, if Err is not nil or Status is not 0, the task
+// returns Status and Err without being called. A Fault holding only a Delay calls the task once the
+// delay has elapsed.
+type Fault struct {
+	Delay  time.Duration
+	Err    error
+	Panic  any
+	Status int
+}
+
+// Injector decides which faults to inject into which tasks. It is safe for concurrent use. Every
+// task has its own call counter and its own random number generator, derived from the seed and the
+// task name, so the n-th call of a task gets the same fault for the same seed regardless of how the
+// calls of different tasks interleave.
+type Injector struct {
+	mutex sync.Mutex
+	rules map[string]*rule
+	seed  uint64
+	tasks map[string]*taskState
+}
+
+// rule holds the faults configured for one task.
+type rule struct {
+	fault       Fault
+	probability float64
+	script      []*Fault
+	scripted    bool
+}
+
+// taskState holds the calls made to one task.
+type taskState struct {
+	calls    int
+	injected int
+	rng      *rand.Rand
+}
+
+// New returns an Injector without any faults whose random decisions are derived from seed.
+func New(seed uint64) *Injector {
+	return &Injector{
+		rules: map[string]*rule{},
+		seed:  seed,
+		tasks: map[string]*taskState{},
+	}
+}
+
+// Always injects fault into every call of task.
+func (in *Injector) Always(task string, fault Fault) {
+	in.Probability(task, 1, fault)
+}
+
+// Calls returns the number of times task was called through this Injector, faults included.
+func (in *Injector) Calls(task string) int {
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+
+	if state, ok := in.tasks[task]; ok {
+		return state.calls
+	}
+
+	return 0
+}
+
+// Injected returns the number of faults injected into task.
+func (in *Injector) Injected(task string) int {
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+
+	if state, ok := in.tasks[task]; ok {
+		return state.injected
+	}
+
+	return 0
+}
+
+// Next decides whether the next call of task gets a fault and returns it.
+func (in *Injector) Next(task string) (Fault, bool) {
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+
+	state := in.stateLocked(task)
+	call := state.calls
+	state.calls++
+
+	r, ok := in.rules[task]
+	if !ok {
+		r, ok = in.rules[AnyTask]
+	}
+
+	if !ok {
+		return Fault{}, false
+	}
+
+	if r.scripted {
+		if call >= len(r.script) || r.script[call] == nil {
+			return Fault{}, false
+		}
+
+		state.injected++
+
+		return *r.script[call], true
+	}
+
+	if r.probability <= 0 || state.rng.Float64() >= r.probability {
+		return Fault{}, false
+	}
+
+	state.injected++
+
+	return r.fault, true
+}
+
+// Probability injects fault into each call of task with the given probability, between 0 and 1.
+// The calls of task are counted from 0 again.
+func (in *Injector) Probability(task string, probability float64, fault Fault) {
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+
+	in.rules[task] = &rule{fault: fault, probability: probability}
+	delete(in.tasks, task)
+}
+
+// Script injects faults[n] into the n-th call of task, counting from 0. A nil element and every call
+// beyond the end of faults leaves the call untouched. The calls of task are counted from 0 again.
+func (in *Injector) Script(task string, faults ...*Fault) {
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+
+	in.rules[task] = &rule{script: faults, scripted: true}
+	delete(in.tasks, task)
+}
+
+// stateLocked returns the calls made to task, starting them over with a random number generator
+// seeded from the seed of this Injector and the name of task. The caller must hold in.mutex.
+func (in *Injector) stateLocked(task string) *taskState {
+	state, ok := in.tasks[task]
+	if !ok {
+		name := fnv.New64a()
+		name.Write([]byte(task))
+
+		state = &taskState{rng: rand.New(rand.NewPCG(in.seed, name.Sum64()))}
+		in.tasks[task] = state
+	}
+
+	return state
+}
+
+// Wrap returns a function, suitable for the Go method of an error status group, that calls f
+// unless a fault is injected in its place.
+func (in *Injector) Wrap(task string, f func() (int, error)) func() (int, error) {
+	return func() (int, error) {
+		return in.WrapContext(task, func(context.Context) (int, error) {
+			return f()
+		})(context.Background())
+	}
+}
+
+// WrapContext is like Wrap for functions taking a context, e.g. those passed to GoWithTimeout.
+// An injected delay ends early when ctx is done, in which case ctx.Err() is returned.
+func (in *Injector) WrapContext(task string, f func(ctx context.Context) (int, error)) func(ctx context.Context) (int, error) {
+	return func(ctx context.Context) (int, error) {
+		return in.call(ctx, task, false, f)
+	}
+}
+
+// WrapErr returns a function, suitable for the Go method of an error group, that calls f unless a
+// fault is injected in its place. The Status of a Fault is ignored, so a fault that only carries a
+// Status still calls f.
+func (in *Injector) WrapErr(task string, f func() error) func() error {
+	return func() error {
+		_, err := in.call(context.Background(), task, true, func(context.Context) (int, error) {
+			return 0, f()
+		})
+
+		return err
+	}
+}
+
+// call calls f with ctx unless the next fault of task is injected in its place. If ignoreStatus is
+// true a fault that only carries a Status is not injected, since error group tasks have no status.
+func (in *Injector) call(ctx context.Context, task string, ignoreStatus bool, f func(ctx context.Context) (int, error)) (int, error) {
+	fault, ok := in.Next(task)
+	if !ok {
+		return f(ctx)
+	}
+
+	if fault.Delay > 0 {
+		timer := time.NewTimer(fault.Delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	if fault.Panic != nil {
+		panic(fault.Panic)
+	}
+
+	if fault.Err != nil || (fault.Status != 0 && !ignoreStatus) {
+		return fault.Status, fault.Err
+	}
+
+	return f(ctx)
+}
@@ -0,0 +1,183 @@
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jgroeneveld/trial/assert"
+	"github.com/seantcanavan/error_group"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errInjected = errors.New("injected")
+
+func TestInjector_Probability(t *testing.T) {
+	outcomes := func(seed uint64) []bool {
+		injector := New(seed)
+		injector.Probability("learner.Search", 0.5, Fault{Status: 503, Err: errInjected})
+
+		var injected []bool
+		for i := 0; i < 100; i++ {
+			_, ok := injector.Next("learner.Search")
+			injected = append(injected, ok)
+		}
+
+		return injected
+	}
+
+	t.Run("verify the same seed injects the same faults", func(t *testing.T) {
+		assert.DeepEqual(t, outcomes(7), outcomes(7))
+	})
+	t.Run("verify roughly the configured share of calls get a fault", func(t *testing.T) {
+		count := 0
+		for _, injected := range outcomes(7) {
+			if injected {
+				count++
+			}
+		}
+
+		assert.True(t, count > 30 && count < 70)
+	})
+}
+
+func TestInjector_Concurrent(t *testing.T) {
+	tasks := []string{"learner.Search", "student.Search", "teacher.Search"}
+
+	schedule := func(seed uint64) map[string][]bool {
+		injector := New(seed)
+		injector.Probability(AnyTask, 0.5, Fault{Err: errInjected})
+
+		var mutex sync.Mutex
+		var wg sync.WaitGroup
+		injected := map[string][]bool{}
+
+		for _, task := range tasks {
+			calls := make([]bool, 50)
+			injected[task] = calls
+
+			for range calls {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					_, ok := injector.Next(task)
+					calls[injector.Calls(task)-1] = ok
+				}()
+			}
+		}
+
+		wg.Wait()
+
+		return injected
+	}
+
+	t.Run("verify the same seed gives the same schedule however concurrent calls interleave", func(t *testing.T) {
+		expected := schedule(42)
+
+		for i := 0; i < 200; i++ {
+			assert.DeepEqual(t, expected, schedule(42))
+		}
+	})
+	t.Run("verify different tasks get different schedules", func(t *testing.T) {
+		expected := schedule(42)
+
+		assert.NotEqual(t, fmt.Sprint(expected["learner.Search"]), fmt.Sprint(expected["teacher.Search"]))
+	})
+}
+
+func TestInjector_Script(t *testing.T) {
+	injector := New(1)
+	injector.Script("teacher.Search", nil, &Fault{Status: 500, Err: errInjected}, nil)
+
+	calls := 0
+	search := injector.Wrap("teacher.Search", func() (int, error) {
+		calls++
+		return 200, nil
+	})
+
+	var statuses []int
+	for i := 0; i < 4; i++ {
+		status, _ := search()
+		statuses = append(statuses, status)
+	}
+
+	t.Run("verify the schedule is followed and then calls pass through", func(t *testing.T) {
+		assert.DeepEqual(t, []int{200, 500, 200, 200}, statuses)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, 4, injector.Calls("teacher.Search"))
+		assert.Equal(t, 1, injector.Injected("teacher.Search"))
+	})
+}
+
+func TestInjector_Groups(t *testing.T) {
+	injector := New(3)
+	injector.Always("learner.Search", Fault{Status: 503, Err: errInjected})
+	injector.Always("student.Search", Fault{Panic: "injected panic"})
+	injector.Always(AnyTask, Fault{Delay: time.Millisecond})
+
+	esg := error_group.NewErrorStatusGroup()
+	esg.Go(injector.Wrap("teacher.Search", func() (int, error) {
+		return 200, nil
+	}))
+	esg.GoOptional(injector.Wrap("learner.Search", func() (int, error) {
+		return 200, nil
+	}))
+	esg.Go(injector.Wrap("student.Search", func() (int, error) {
+		return 200, nil
+	}))
+
+	status, err := esg.Wait()
+
+	t.Run("verify injected faults exercise the partial failure paths", func(t *testing.T) {
+		assert.Equal(t, 500, status)
+		assert.NotNil(t, err)
+		assert.Equal(t, 1, len(esg.Warnings()))
+		assert.Equal(t, 1, len(error_group.AsAll[*error_group.PanicError](esg)))
+	})
+	t.Run("verify AnyTask applies to tasks without faults of their own", func(t *testing.T) {
+		assert.Equal(t, 1, injector.Injected("teacher.Search"))
+		assert.Equal(t, 0, injector.Injected(AnyTask))
+	})
+	t.Run("verify WrapErr() injects errors into error group tasks", func(t *testing.T) {
+		eg := error_group.NewErrorGroup()
+		eg.Go(injector.WrapErr("learner.Search", func() error {
+			return nil
+		}))
+
+		eg.Wait()
+
+		assert.True(t, errors.Is(eg.Find(errInjected), errInjected))
+	})
+	t.Run("verify WrapErr() calls the task when the fault only carries a status", func(t *testing.T) {
+		statusOnly := New(1)
+		statusOnly.Always("teacher.Search", Fault{Status: 503})
+
+		errCalled := errors.New("task called")
+		eg := error_group.NewErrorGroup()
+		eg.Go(statusOnly.WrapErr("teacher.Search", func() error {
+			return errCalled
+		}))
+
+		eg.Wait()
+
+		assert.True(t, errors.Is(eg.Find(errCalled), errCalled))
+		assert.Equal(t, 1, eg.Len())
+	})
+	t.Run("verify an injected delay respects the task's context", func(t *testing.T) {
+		slow := New(1)
+		slow.Always("slow", Fault{Delay: time.Hour})
+
+		esg := error_group.NewErrorStatusGroup()
+		esg.GoWithTimeout("slow", 10*time.Millisecond, slow.WrapContext("slow", func(ctx context.Context) (int, error) {
+			return 200, nil
+		}))
+		status, _ := esg.Wait()
+
+		assert.Equal(t, 504, status)
+	})
+}
@@ -1,6 +1,6 @@
 module github.com/seantcanavan/error_group
 
-go 1.18
+go 1.23
 
 require github.com/jgroeneveld/trial v2.0.0+incompatible
 
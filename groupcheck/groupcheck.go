@@ -0,0 +1,524 @@
+// Package groupcheck defines an Analyzer that reports common misuses of error groups and error
+// status groups:
+//
+//   - groups that are created and filled but whose errors are never read, returned or waited for,
+//     outside of test files and unless the group reports to a Registry or an Observer,
+//   - calls to First, Last, FirstError, LastError, FirstStatus and LastStatus that are not guarded
+//     by a check of Len, LenErrors or LenStatuses, since they panic on an empty group,
+//   - reads of a group after it escaped to a goroutine, either through a go statement or a call of
+//     one of the group's Go methods whose result is discarded, without a Wait, channel receive or
+//     select in between.
+//
+// The Analyzer can be run on its own with cmd/groupcheck or through go vet:
+//
+//	go vet -vettool=$(which groupcheck) ./...
+package groupcheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Analyzer reports unchecked groups, unguarded First and Last calls and unsynchronized reads of
+// groups shared with goroutines.
+var Analyzer = &analysis.Analyzer{
+	Name:     "groupcheck",
+	Doc:      "report unchecked error groups, unguarded First/Last calls and unsynchronized reads of groups shared with goroutines",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// errorGroupPath is the import path of the package defining the group types.
+const errorGroupPath = "github.com/seantcanavan/error_group"
+
+// escapes are the group methods that hand the group to a new goroutine.
+var escapes = map[string]bool{
+	"Go":             true,
+	"GoOptional":     true,
+	"GoWithDeadline": true,
+	"GoWithTimeout":  true,
+}
+
+// guards maps each method that panics on an empty group to the method that guards it.
+var guards = map[string]string{
+	"First":       "Len",
+	"FirstError":  "LenErrors",
+	"FirstStatus": "LenStatuses",
+	"Last":        "Len",
+	"LastError":   "LenErrors",
+	"LastStatus":  "LenStatuses",
+}
+
+// results are the methods whose return value carries the group's errors.
+var results = map[string]bool{
+	"Error":            true,
+	"ToError":          true,
+	"ToStatusAndError": true,
+	"Wait":             true,
+}
+
+// writes are the group methods that only add values to the group or manage its lifecycle.
+var writes = map[string]bool{
+	"Add":                       true,
+	"AddError":                  true,
+	"AddFatal":                  true,
+	"AddGroup":                  true,
+	"AddOptionalStatusAndError": true,
+	"AddStatus":                 true,
+	"AddStatusAndError":         true,
+	"AddWarning":                true,
+	"Close":                     true,
+	"Go":                        true,
+	"GoOptional":                true,
+	"GoWithDeadline":            true,
+	"GoWithTimeout":             true,
+	"Merge":                     true,
+	"Reset":                     true,
+}
+
+// reads are the group methods whose result depends on the values added to the group so far. Drain
+// and Snapshot are left out since they are meant to be called while tasks are running.
+var reads = map[string]bool{
+	"All":              true,
+	"Degraded":         true,
+	"Entries":          true,
+	"Error":            true,
+	"Errors":           true,
+	"Filter":           true,
+	"Find":             true,
+	"First":            true,
+	"FirstError":       true,
+	"FirstStatus":      true,
+	"HasStatus":        true,
+	"HighestStatus":    true,
+	"Last":             true,
+	"LastError":        true,
+	"LastStatus":       true,
+	"Len":              true,
+	"LenErrors":        true,
+	"LenStatuses":      true,
+	"LowestStatus":     true,
+	"OptionalStatuses": true,
+	"Outcome":          true,
+	"Partition":        true,
+	"StatusHistogram":  true,
+	"Statuses":         true,
+	"SuccessRate":      true,
+	"ToError":          true,
+	"ToStatusAndError": true,
+	"Unwrap":           true,
+	"Warnings":         true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if push {
+			checkGuard(pass, n.(*ast.CallExpr), stack)
+		}
+
+		return true
+	})
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+
+		if body != nil {
+			checkFunc(pass, n, body)
+		}
+	})
+
+	return nil, nil
+}
+
+// use is a reference to a local group variable.
+type use struct {
+	ident  *ast.Ident
+	method string
+	parent ast.Node
+}
+
+// checkFunc reports unchecked groups and unsynchronized reads of the group variables and parameters
+// declared by fn, whose body is body. Variables declared by nested function literals are checked
+// with those literals.
+func checkFunc(pass *analysis.Pass, fn ast.Node, body *ast.BlockStmt) {
+	uses := map[*types.Var][]use{}
+	var defs []*ast.Ident
+	var goStmts []*ast.GoStmt
+	var syncs []token.Pos
+
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+
+		var parent ast.Node
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+		stack = append(stack, n)
+
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			collectFuncLit(pass, node, uses, &goStmts, &syncs)
+			stack = stack[:len(stack)-1]
+
+			return false
+		case *ast.GoStmt:
+			goStmts = append(goStmts, node)
+		case *ast.SelectStmt:
+			syncs = append(syncs, node.Pos())
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				syncs = append(syncs, node.Pos())
+			}
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Wait" {
+				syncs = append(syncs, node.End())
+			}
+		case *ast.Ident:
+			if obj, ok := pass.TypesInfo.Defs[node].(*types.Var); ok && isGroup(obj.Type()) && created(pass, node, parent) {
+				defs = append(defs, node)
+			}
+
+			if obj, ok := pass.TypesInfo.Uses[node].(*types.Var); ok && isGroup(obj.Type()) {
+				uses[obj] = append(uses[obj], newUse(node, stack))
+			}
+		}
+
+		return true
+	})
+
+	for _, def := range defs {
+		obj := pass.TypesInfo.Defs[def].(*types.Var)
+		if !checked(uses[obj]) && !strings.HasSuffix(pass.Fset.File(def.Pos()).Name(), "_test.go") {
+			pass.Reportf(def.Pos(), "error group %s is never checked: its errors are never read, returned or waited for", def.Name)
+		}
+	}
+
+	for obj, objUses := range uses {
+		if contains(fn, obj.Pos()) {
+			checkEscapes(pass, obj, objUses, goStmts, syncs)
+		}
+	}
+}
+
+// collectFuncLit records the uses of captured groups, the go statements and the synchronization
+// points of a function literal nested in the function being checked.
+func collectFuncLit(pass *analysis.Pass, lit *ast.FuncLit, uses map[*types.Var][]use, goStmts *[]*ast.GoStmt, syncs *[]token.Pos) {
+	var stack []ast.Node
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			*goStmts = append(*goStmts, node)
+		case *ast.SelectStmt:
+			*syncs = append(*syncs, node.Pos())
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				*syncs = append(*syncs, node.Pos())
+			}
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Wait" {
+				*syncs = append(*syncs, node.End())
+			}
+		case *ast.Ident:
+			obj, ok := pass.TypesInfo.Uses[node].(*types.Var)
+			if ok && isGroup(obj.Type()) && !contains(lit, obj.Pos()) {
+				uses[obj] = append(uses[obj], newUse(node, stack))
+			}
+		}
+
+		return true
+	})
+}
+
+// created reports whether ident, declared by parent, holds a new group: a zero value, a composite
+// literal or the result of one of the constructors of the error_group package that does not report
+// to a Registry or an Observer.
+func created(pass *analysis.Pass, ident *ast.Ident, parent ast.Node) bool {
+	var lhs []*ast.Ident
+	var rhs []ast.Expr
+
+	switch decl := parent.(type) {
+	case *ast.AssignStmt:
+		for _, expr := range decl.Lhs {
+			id, _ := expr.(*ast.Ident)
+			lhs = append(lhs, id)
+		}
+		rhs = decl.Rhs
+	case *ast.ValueSpec:
+		lhs = decl.Names
+		rhs = decl.Values
+	default:
+		return false
+	}
+
+	if ident.Name == "_" {
+		return false
+	}
+
+	if len(rhs) == 0 {
+		// var g error_group.ErrorGroup declares a ready to use zero value.
+		_, isPointer := pass.TypesInfo.TypeOf(ident).(*types.Pointer)
+
+		return !isPointer
+	}
+
+	value := rhs[0]
+	if len(rhs) == len(lhs) {
+		for i, id := range lhs {
+			if id == ident {
+				value = rhs[i]
+			}
+		}
+	}
+
+	if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		value = unary.X
+	}
+
+	if _, ok := value.(*ast.CompositeLit); ok {
+		return true
+	}
+
+	call, ok := value.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	constructor := typeutil.Callee(pass.TypesInfo, call)
+	if constructor == nil || constructor.Pkg() == nil || constructor.Pkg().Path() != errorGroupPath || !strings.HasPrefix(constructor.Name(), "New") {
+		return false
+	}
+
+	for _, arg := range call.Args {
+		if mentions(arg, "", "Observe") || mentions(arg, "", "Register") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newUse describes the reference ident at the top of stack.
+func newUse(ident *ast.Ident, stack []ast.Node) use {
+	u := use{ident: ident}
+
+	if len(stack) >= 2 {
+		if sel, ok := stack[len(stack)-2].(*ast.SelectorExpr); ok && sel.X == ident {
+			if len(stack) >= 3 {
+				if call, ok := stack[len(stack)-3].(*ast.CallExpr); ok && call.Fun == sel {
+					u.method = sel.Sel.Name
+					if len(stack) >= 4 {
+						u.parent = stack[len(stack)-4]
+					}
+				}
+			}
+		}
+	}
+
+	return u
+}
+
+// checked reports whether any of uses reads the group, returns its errors or hands it to other
+// code.
+func checked(uses []use) bool {
+	for _, u := range uses {
+		switch {
+		case u.method == "":
+			return true
+		case results[u.method]:
+			if _, discarded := u.parent.(*ast.ExprStmt); !discarded {
+				return true
+			}
+		case !writes[u.method]:
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkEscapes reports reads of obj that happen after obj escaped to a goroutine without a
+// synchronization point in between.
+func checkEscapes(pass *analysis.Pass, obj *types.Var, uses []use, goStmts []*ast.GoStmt, syncs []token.Pos) {
+	var escaped []token.Pos
+
+	for _, goStmt := range goStmts {
+		for _, u := range uses {
+			if contains(goStmt, u.ident.Pos()) {
+				escaped = append(escaped, goStmt.End())
+				break
+			}
+		}
+	}
+
+	for _, u := range uses {
+		if _, discarded := u.parent.(*ast.ExprStmt); discarded && escapes[u.method] {
+			escaped = append(escaped, u.ident.End())
+		}
+	}
+
+	for _, u := range uses {
+		if !reads[u.method] {
+			continue
+		}
+
+		for _, escape := range escaped {
+			if u.ident.Pos() > escape && !synced(syncs, escape, u.ident.Pos()) {
+				pass.Reportf(u.ident.Pos(), "%s.%s reads error group %s while goroutines may still be adding to it; call Wait first", obj.Name(), u.method, obj.Name())
+				break
+			}
+		}
+	}
+}
+
+// synced reports whether a synchronization point lies between from and to.
+func synced(syncs []token.Pos, from, to token.Pos) bool {
+	for _, pos := range syncs {
+		if pos > from && pos <= to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkGuard reports call if it invokes a method that panics on an empty group and is not guarded
+// by a check of the matching length method on the same group.
+func checkGuard(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+
+	guard, ok := guards[sel.Sel.Name]
+	if !ok || !isGroup(pass.TypesInfo.TypeOf(sel.X)) {
+		return
+	}
+
+	receiver := types.ExprString(sel.X)
+
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.IfStmt:
+			if contains(node.Body, call.Pos()) && mentions(node.Cond, receiver, guard) {
+				return
+			}
+		case *ast.CaseClause:
+			for _, expr := range node.List {
+				if mentions(expr, receiver, guard) {
+					return
+				}
+			}
+		case *ast.BlockStmt:
+			for _, stmt := range node.List {
+				if stmt.Pos() >= call.Pos() {
+					break
+				}
+
+				if ifStmt, ok := stmt.(*ast.IfStmt); ok && mentions(ifStmt.Cond, receiver, guard) && terminates(ifStmt.Body) {
+					return
+				}
+			}
+		case *ast.FuncDecl, *ast.FuncLit:
+			i = 0
+		}
+	}
+
+	pass.Reportf(call.Pos(), "%s.%s panics on an empty group; check %s.%s first", receiver, sel.Sel.Name, receiver, guard)
+}
+
+// mentions reports whether expr calls method on the receiver spelled receiver or, if receiver is
+// empty, calls a function named method.
+func mentions(expr ast.Expr, receiver, method string) bool {
+	found := false
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				found = found || receiver == "" && fun.Name == method
+			case *ast.SelectorExpr:
+				if fun.Sel.Name == method {
+					_, qualified := fun.X.(*ast.Ident)
+					found = found || receiver == "" && qualified || types.ExprString(fun.X) == receiver
+				}
+			}
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// terminates reports whether block ends by leaving the enclosing block.
+func terminates(block *ast.BlockStmt) bool {
+	if len(block.List) == 0 {
+		return false
+	}
+
+	switch last := block.List[len(block.List)-1].(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		if call, ok := last.X.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+				return true
+			}
+
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && strings.HasPrefix(sel.Sel.Name, "Fatal") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// contains reports whether pos lies within node.
+func contains(node ast.Node, pos token.Pos) bool {
+	return node.Pos() <= pos && pos < node.End()
+}
+
+// isGroup reports whether t is an error group or an error status group, or a pointer to one.
+func isGroup(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != errorGroupPath {
+		return false
+	}
+
+	switch named.Obj().Name() {
+	case "ErrorGroup", "ErrorStatusGroup":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,207 @@
+package a
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/seantcanavan/error_group"
+)
+
+func unchecked() error {
+	eg := error_group.NewErrorGroup() // want `error group eg is never checked`
+	eg.Add(errors.New("lost"))
+
+	return nil
+}
+
+func uncheckedWait() {
+	eg := error_group.NewErrorGroup() // want `error group eg is never checked`
+	eg.Go(func() error { return errors.New("lost") })
+	eg.Wait()
+}
+
+func checked() error {
+	eg := error_group.NewErrorGroup()
+	eg.Go(func() error { return nil })
+
+	return eg.Wait()
+}
+
+func passed(use func(interface{ Len() int })) {
+	eg := error_group.NewErrorGroup()
+	eg.Add(errors.New("handed over"))
+	use(eg)
+}
+
+func unguarded() error {
+	group := error_group.NewErrorGroup()
+	group.Add(errors.New("a"))
+
+	return group.First() // want `group.First panics on an empty group; check group.Len first`
+}
+
+func guardedIf() error {
+	eg := error_group.NewErrorGroup()
+	if eg.Len() > 0 {
+		return eg.First()
+	}
+
+	return nil
+}
+
+func guardedReturn() int {
+	esg := error_group.NewErrorStatusGroup()
+	if esg.LenStatuses() == 0 {
+		return 200
+	}
+
+	return esg.FirstStatus()
+}
+
+func wrongGuard() error {
+	eg, other := error_group.NewErrorGroup(), error_group.NewErrorGroup()
+	if other.Len() > 0 {
+		return eg.Last() // want `eg.Last panics on an empty group; check eg.Len first`
+	}
+
+	return nil
+}
+
+func unsynchronized() int {
+	eg := error_group.NewErrorGroup()
+	eg.Go(func() error { return nil })
+
+	return eg.Len() // want `eg.Len reads error group eg while goroutines may still be adding to it`
+}
+
+func goStatement() (int, error) {
+	esg := error_group.NewErrorStatusGroup()
+	go func() {
+		esg.AddStatus(500)
+	}()
+
+	return esg.ToStatusAndError() // want `esg.ToStatusAndError reads error group esg while goroutines may still be adding to it`
+}
+
+func waitGroup() error {
+	eg := error_group.NewErrorGroup()
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		eg.Add(errors.New("a"))
+	}()
+
+	wg.Wait()
+
+	return eg.ToError()
+}
+
+func channel() error {
+	eg := error_group.NewErrorGroup()
+	done := make(chan struct{})
+
+	go func() {
+		eg.Add(errors.New("a"))
+		close(done)
+	}()
+
+	<-done
+
+	return eg.ToError()
+}
+
+func registered(registry *error_group.Registry) {
+	eg := error_group.NewErrorGroup(error_group.Register(registry, "batch"))
+	eg.Add(errors.New("observed through the registry"))
+}
+
+func observed(observer error_group.Observer) {
+	esg := error_group.NewErrorStatusGroup(error_group.Observe(observer))
+	esg.AddStatus(500)
+}
+
+func unregistered() {
+	esg := error_group.NewErrorStatusGroup() // want `error group esg is never checked`
+	esg.AddStatus(500)
+}
+
+func drained(stream chan<- error) {
+	eg := error_group.NewErrorGroup()
+	eg.Go(func() error { return nil })
+
+	if drained := eg.Drain(); drained.Len() > 0 {
+		for _, err := range drained.All() {
+			stream <- err
+		}
+	}
+
+	esg := error_group.NewErrorStatusGroup()
+	esg.Go(func() (int, error) { return 200, nil })
+
+	if esg.Drain().LenStatuses() > 0 {
+		stream <- nil
+	}
+
+	eg.Wait()
+	esg.Wait()
+}
+
+func rejected() error {
+	eg := error_group.NewErrorGroup()
+	if !eg.Go(func() error { return nil }) {
+		return eg.ToError()
+	}
+
+	run := func() error {
+		eg.Wait()
+
+		return eg.ToError()
+	}
+
+	return run()
+}
+
+func zeroValue() error {
+	var eg error_group.ErrorGroup // want `error group eg is never checked`
+	eg.Add(errors.New("lost"))
+
+	return nil
+}
+
+func literal() {
+	esg := &error_group.ErrorStatusGroup{} // want `error group esg is never checked`
+	esg.AddStatus(500)
+}
+
+func zeroValueUnguarded() error {
+	var eg error_group.ErrorGroup
+	eg.Add(errors.New("a"))
+
+	return eg.First() // want `eg.First panics on an empty group; check eg.Len first`
+}
+
+func zeroValueUnsynchronized() int {
+	var esg error_group.ErrorStatusGroup
+	esg.Go(func() (int, error) { return 200, nil })
+
+	return esg.LenStatuses() // want `esg.LenStatuses reads error group esg while goroutines may still be adding to it`
+}
+
+type handler struct {
+	errors   error_group.ErrorGroup
+	statuses error_group.ErrorStatusGroup
+}
+
+func (h *handler) firstError() error {
+	return h.errors.First() // want `h.errors.First panics on an empty group; check h.errors.Len first`
+}
+
+func (h *handler) firstStatus() int {
+	if h.statuses.LenStatuses() == 0 {
+		return 200
+	}
+
+	return h.statuses.FirstStatus()
+}
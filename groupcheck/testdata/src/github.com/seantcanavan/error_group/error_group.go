@@ -0,0 +1,58 @@
+// Package error_group is a stub of the real package with the same signatures for the methods the
+// analyzer tests use.
+package error_group
+
+type config struct {
+	observers []Observer
+	registry  *Registry
+}
+
+type Option func(*config)
+
+type Observer interface{}
+
+type Registry struct{}
+
+func NewRegistry() *Registry { return &Registry{} }
+
+func Observe(observer Observer) Option {
+	return func(cfg *config) { cfg.observers = append(cfg.observers, observer) }
+}
+
+func Register(registry *Registry, name string) Option {
+	return func(cfg *config) { cfg.registry = registry }
+}
+
+type Snapshot struct{ errors []error }
+
+func (s Snapshot) All() []error { return s.errors }
+func (s Snapshot) Len() int     { return len(s.errors) }
+
+type ErrorGroup struct{ errors []error }
+
+func NewErrorGroup(opts ...Option) *ErrorGroup { return &ErrorGroup{} }
+
+func (eg *ErrorGroup) Add(err error)          { eg.errors = append(eg.errors, err) }
+func (eg *ErrorGroup) Drain() Snapshot        { return Snapshot{errors: eg.errors} }
+func (eg *ErrorGroup) First() error           { return eg.errors[0] }
+func (eg *ErrorGroup) Go(f func() error) bool { return true }
+func (eg *ErrorGroup) Last() error            { return eg.errors[len(eg.errors)-1] }
+func (eg *ErrorGroup) Len() int               { return len(eg.errors) }
+func (eg *ErrorGroup) ToError() error         { return nil }
+func (eg *ErrorGroup) Wait() error            { return nil }
+
+type StatusSnapshot struct{ statuses []int }
+
+func (s StatusSnapshot) LenStatuses() int { return len(s.statuses) }
+
+type ErrorStatusGroup struct{ statuses []int }
+
+func NewErrorStatusGroup(opts ...Option) *ErrorStatusGroup { return &ErrorStatusGroup{} }
+
+func (esg *ErrorStatusGroup) AddStatus(status int)           { esg.statuses = append(esg.statuses, status) }
+func (esg *ErrorStatusGroup) Drain() StatusSnapshot          { return StatusSnapshot{statuses: esg.statuses} }
+func (esg *ErrorStatusGroup) FirstStatus() int               { return esg.statuses[0] }
+func (esg *ErrorStatusGroup) Go(f func() (int, error)) bool  { return true }
+func (esg *ErrorStatusGroup) LenStatuses() int               { return len(esg.statuses) }
+func (esg *ErrorStatusGroup) ToStatusAndError() (int, error) { return 0, nil }
+func (esg *ErrorStatusGroup) Wait() (int, error)             { return 0, nil }
@@ -0,0 +1,178 @@
+package error_group
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// StatusHistogram counts the status values saved to an error status group, both per status value
+// and per class of status value ("1xx" through "5xx", and "other" for anything outside of them).
+type StatusHistogram struct {
+	Classes map[string]int `json:"classes"`
+	Codes   map[int]int    `json:"codes"`
+	Total   int            `json:"total"`
+}
+
+// StatusHistogram counts the status values saved to this error status group instance and its child
+// groups. Optional status values are not counted.
+func (esg *ErrorStatusGroup) StatusHistogram() StatusHistogram {
+	return esg.Snapshot().StatusHistogram()
+}
+
+// SuccessRate returns the fraction of the status values saved to this error status group instance
+// and its child groups that are below 400. It returns 1 when no status value has been saved.
+func (esg *ErrorStatusGroup) SuccessRate() float64 {
+	return esg.StatusHistogram().SuccessRate()
+}
+
+// StatusHistogram counts the status values captured by this snapshot and its child snapshots.
+func (s StatusSnapshot) StatusHistogram() StatusHistogram {
+	histogram := StatusHistogram{
+		Classes: map[string]int{},
+		Codes:   map[int]int{},
+	}
+
+	s.countStatuses(histogram)
+
+	for _, count := range histogram.Codes {
+		histogram.Total += count
+	}
+
+	return histogram
+}
+
+// SuccessRate returns the fraction of the status values captured by this snapshot and its child
+// snapshots that are below 400. It returns 1 when no status value was captured.
+func (s StatusSnapshot) SuccessRate() float64 {
+	return s.StatusHistogram().SuccessRate()
+}
+
+// countStatuses adds the status values of this snapshot and its child snapshots to histogram.
+func (s StatusSnapshot) countStatuses(histogram StatusHistogram) {
+	for _, entry := range s.entries {
+		if entry.HasStatus {
+			histogram.Codes[entry.Status]++
+			histogram.Classes[statusClass(entry.Status)]++
+		}
+	}
+
+	for _, child := range s.groups {
+		child.snapshot.countStatuses(histogram)
+	}
+}
+
+// ClassRate returns the fraction of the counted status values that belong to class, e.g. "5xx".
+// It returns 0 when no status value was counted.
+func (sh StatusHistogram) ClassRate(class string) float64 {
+	if sh.Total == 0 {
+		return 0
+	}
+
+	return float64(sh.Classes[class]) / float64(sh.Total)
+}
+
+// SuccessRate returns the fraction of the counted status values that are below 400. It returns 1
+// when no status value was counted.
+func (sh StatusHistogram) SuccessRate() float64 {
+	if sh.Total == 0 {
+		return 1
+	}
+
+	successes := 0
+	for code, count := range sh.Codes {
+		if code < 400 {
+			successes += count
+		}
+	}
+
+	return float64(successes) / float64(sh.Total)
+}
+
+// writeStatusHeader writes the per status and per class counts of the status values captured by
+// this snapshot and its child snapshots as two lines, counted the same way StatusHistogram counts
+// them. It runs on every call to Error so it works from a sorted slice of the status values rather
+// than from the maps of a StatusHistogram.
+func (s StatusSnapshot) writeStatusHeader(sb *strings.Builder) {
+	statuses := s.appendStatuses(make([]int, 0, s.statusCount))
+	slices.Sort(statuses)
+
+	// classes holds the count of "other" followed by those of "1xx" through "5xx".
+	var classes [6]int
+
+	sb.WriteString("status counts: [")
+	for i := 0; i < len(statuses); {
+		code := statuses[i]
+
+		count := 1
+		for i+count < len(statuses) && statuses[i+count] == code {
+			count++
+		}
+
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteString(strconv.Itoa(code))
+		sb.WriteString(": ")
+		sb.WriteString(strconv.Itoa(count))
+
+		if code >= 100 && code <= 599 {
+			classes[code/100] += count
+		} else {
+			classes[0] += count
+		}
+
+		i += count
+	}
+	sb.WriteString("]\n")
+
+	sb.WriteString("status classes: [")
+	written := false
+	for i := 1; i <= len(classes); i++ {
+		class := i % len(classes)
+		if classes[class] == 0 {
+			continue
+		}
+
+		if written {
+			sb.WriteString(", ")
+		}
+
+		if class == 0 {
+			sb.WriteString("other")
+		} else {
+			sb.WriteByte(byte('0' + class))
+			sb.WriteString("xx")
+		}
+
+		sb.WriteString(": ")
+		sb.WriteString(strconv.Itoa(classes[class]))
+		written = true
+	}
+	sb.WriteString("]\n")
+}
+
+// appendStatuses appends the status values of this snapshot and its child snapshots to statuses.
+func (s StatusSnapshot) appendStatuses(statuses []int) []int {
+	for _, entry := range s.entries {
+		if entry.HasStatus {
+			statuses = append(statuses, entry.Status)
+		}
+	}
+
+	for _, child := range s.groups {
+		statuses = child.snapshot.appendStatuses(statuses)
+	}
+
+	return statuses
+}
+
+// statusClass returns the class status belongs to.
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "other"
+	}
+
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,48 @@
+package error_group
+
+import (
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"testing"
+)
+
+func TestErrorStatusGroup_StatusHistogram(t *testing.T) {
+	child := NewErrorStatusGroup()
+	child.AddStatus(503)
+
+	esg := NewErrorStatusGroup()
+	esg.AddStatus(200)
+	esg.AddStatus(200)
+	esg.AddStatusAndError(404, errors.New("not found"))
+	esg.AddStatus(42)
+	esg.AddError(errors.New("no status"))
+	esg.AddOptionalStatusAndError(500, nil)
+	esg.AddGroup("child", child)
+
+	histogram := esg.StatusHistogram()
+
+	t.Run("verify StatusHistogram() counts every status value of the hierarchy", func(t *testing.T) {
+		assert.Equal(t, 5, histogram.Total)
+		assert.DeepEqual(t, map[int]int{42: 1, 200: 2, 404: 1, 503: 1}, histogram.Codes)
+	})
+	t.Run("verify StatusHistogram() counts every class", func(t *testing.T) {
+		assert.DeepEqual(t, map[string]int{"2xx": 2, "4xx": 1, "5xx": 1, "other": 1}, histogram.Classes)
+	})
+	t.Run("verify the ratio helpers", func(t *testing.T) {
+		assert.Equal(t, 0.6, esg.SuccessRate())
+		assert.Equal(t, 0.2, histogram.ClassRate("5xx"))
+		assert.Equal(t, 0.0, histogram.ClassRate("1xx"))
+	})
+	t.Run("verify an empty group has a success rate of 1", func(t *testing.T) {
+		empty := NewErrorStatusGroup()
+		assert.Equal(t, 1.0, empty.SuccessRate())
+		assert.Equal(t, 0.0, empty.StatusHistogram().ClassRate("2xx"))
+	})
+	t.Run("verify Error() includes the breakdown in its header", func(t *testing.T) {
+		expected := "lowest status: [42]\nhighest status: [503]\n" +
+			"status counts: [42: 1, 200: 2, 404: 1, 503: 1]\n" +
+			"status classes: [2xx: 2, 4xx: 1, 5xx: 1, other: 1]\n" +
+			"not found\nno status"
+		assert.Equal(t, expected, esg.Error())
+	})
+}
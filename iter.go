@@ -0,0 +1,75 @@
+package error_group
+
+import "iter"
+
+// Entries returns an iterator over the index and value of every error saved to this error group
+// instance. The errors are captured when iteration starts without being copied, so breaking out
+// of the loop early avoids the cost of All on large groups. Errors of child groups are not
+// included.
+func (eg *ErrorGroup) Entries() iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		eg.mutex.Lock()
+		errs := eg.errors[:len(eg.errors):len(eg.errors)]
+		eg.mutex.Unlock()
+
+		for i, currentError := range errs {
+			if !yield(i, currentError) {
+				return
+			}
+		}
+	}
+}
+
+// Errors returns an iterator over every error saved to this error group instance. See Entries
+// for how the errors are captured.
+func (eg *ErrorGroup) Errors() iter.Seq[error] {
+	return func(yield func(error) bool) {
+		for _, currentError := range eg.Entries() {
+			if !yield(currentError) {
+				return
+			}
+		}
+	}
+}
+
+// Entries returns an iterator over the index and value of every entry saved to this error status
+// group instance. The entries are captured when iteration starts without being copied, so breaking
+// out of the loop early avoids the cost of All on large groups. Entries of child groups are not
+// included.
+func (esg *ErrorStatusGroup) Entries() iter.Seq2[int, Entry] {
+	return func(yield func(int, Entry) bool) {
+		esg.mutex.Lock()
+		entries := esg.entries[:len(esg.entries):len(esg.entries)]
+		esg.mutex.Unlock()
+
+		for i, entry := range entries {
+			if !yield(i, entry) {
+				return
+			}
+		}
+	}
+}
+
+// Errors returns an iterator over every error value saved to this error status group instance.
+// See Entries for how the values are captured.
+func (esg *ErrorStatusGroup) Errors() iter.Seq[error] {
+	return func(yield func(error) bool) {
+		for _, entry := range esg.Entries() {
+			if entry.Err != nil && !yield(entry.Err) {
+				return
+			}
+		}
+	}
+}
+
+// Statuses returns an iterator over every status value saved to this error status group instance.
+// See Entries for how the values are captured.
+func (esg *ErrorStatusGroup) Statuses() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for _, entry := range esg.Entries() {
+			if entry.HasStatus && !yield(entry.Status) {
+				return
+			}
+		}
+	}
+}
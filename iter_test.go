@@ -0,0 +1,89 @@
+package error_group
+
+import (
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"testing"
+)
+
+func TestErrorGroup_Errors(t *testing.T) {
+	eg := NewErrorGroup()
+	eg.Add(errors.New("first message"))
+	eg.Add(errors.New("middle message"))
+	eg.Add(errors.New("last message"))
+
+	t.Run("verify Errors() yields every error in order", func(t *testing.T) {
+		var messages []string
+		for err := range eg.Errors() {
+			messages = append(messages, err.Error())
+		}
+
+		assert.DeepEqual(t, []string{"first message", "middle message", "last message"}, messages)
+	})
+	t.Run("verify Entries() yields indexes and stops early", func(t *testing.T) {
+		visited := 0
+		for i, err := range eg.Entries() {
+			visited++
+			if err.Error() == "middle message" {
+				assert.Equal(t, 1, i)
+				break
+			}
+		}
+
+		assert.Equal(t, 2, visited)
+	})
+	t.Run("verify Errors() is not affected by Add() during iteration", func(t *testing.T) {
+		visited := 0
+		for range eg.Errors() {
+			eg.Add(errors.New(generateRandomString(10)))
+			visited++
+		}
+
+		assert.Equal(t, 3, visited)
+		assert.Equal(t, 6, eg.Len())
+	})
+}
+
+func TestErrorStatusGroup_Entries(t *testing.T) {
+	esg := NewErrorStatusGroup()
+	esg.AddStatusAndError(500, errors.New("first message"))
+	esg.AddStatus(200)
+	esg.AddError(errors.New("last message"))
+
+	t.Run("verify Entries() yields every entry with its index", func(t *testing.T) {
+		var entries []Entry
+		for i, entry := range esg.Entries() {
+			assert.Equal(t, len(entries), i)
+			entries = append(entries, entry)
+		}
+
+		assert.Equal(t, 3, len(entries))
+		assert.Equal(t, 500, entries[0].Status)
+		assert.False(t, entries[2].HasStatus)
+	})
+	t.Run("verify Errors() only yields error values", func(t *testing.T) {
+		var messages []string
+		for err := range esg.Errors() {
+			messages = append(messages, err.Error())
+		}
+
+		assert.DeepEqual(t, []string{"first message", "last message"}, messages)
+	})
+	t.Run("verify Statuses() only yields status values", func(t *testing.T) {
+		var statuses []int
+		for status := range esg.Statuses() {
+			statuses = append(statuses, status)
+		}
+
+		assert.DeepEqual(t, []int{500, 200}, statuses)
+	})
+	t.Run("verify Statuses() stops early", func(t *testing.T) {
+		visited := 0
+		for range esg.Statuses() {
+			visited++
+			break
+		}
+
+		assert.Equal(t, 1, visited)
+	})
+}
@@ -0,0 +1,43 @@
+package error_group
+
+// Option configures an error group or error status group instance when passed to
+// NewErrorGroup or NewErrorStatusGroup.
+type Option func(*config)
+
+// config holds the settings applied by Option values. Its zero value is the default configuration
+// so that the zero value of a group is usable.
+type config struct {
+	clock            Clock
+	hasMaxErrorRate  bool
+	hasMaxErrors     bool
+	maxErrorRate     float64
+	maxErrors        int
+	minSamples       int
+	name             string
+	observers        []Observer
+	order            Ordering
+	panicOnLateWrite bool
+	registry         *Registry
+	retry            *RetryPolicy
+	statusMap        *StatusMap
+	statusThreshold  int
+}
+
+// newConfig applies opts to the default configuration.
+func newConfig(opts []Option) config {
+	var cfg config
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// PanicOnLateWrite makes a group panic with ErrGroupClosed when a value is added after Close
+// has been called instead of silently discarding it. It is intended for debug builds and tests.
+func PanicOnLateWrite() Option {
+	return func(cfg *config) {
+		cfg.panicOnLateWrite = true
+	}
+}
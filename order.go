@@ -0,0 +1,126 @@
+package error_group
+
+import (
+	"cmp"
+	"slices"
+)
+
+// orderKind tells the built-in orderings apart.
+type orderKind int
+
+const (
+	orderInsertion orderKind = iota
+	orderSubmission
+	orderCompare
+)
+
+// Ordering decides the order in which All, Error and the JSON serialization of a group and its
+// snapshots report the values of the group. Values are always appended in the order they arrive;
+// the ordering is applied when they are read. Iterators, First, Last and Warnings always use
+// insertion order.
+type Ordering struct {
+	compare func(a, b Entry) int
+	kind    orderKind
+}
+
+var (
+	// InsertionOrder reports values in the order they were added to the group. It is the default.
+	InsertionOrder = Ordering{}
+	// SubmissionOrder reports the values produced by functions started with Go, or one of its
+	// variants, in the order the functions were started rather than the order they returned in.
+	// Values added directly to the group count as submitted when they are added.
+	SubmissionOrder = Ordering{kind: orderSubmission}
+	// StatusThenMessageOrder sorts values by status value, values without a status first, and
+	// values with the same status by error message.
+	StatusThenMessageOrder = OrderBy(compareStatusThenMessage)
+)
+
+// OrderBy returns an Ordering that sorts values with compare, which must return a negative number
+// when a sorts before b, a positive number when a sorts after b and zero otherwise. Values that
+// compare equal keep their insertion order. Entries of an error group only carry an error.
+func OrderBy(compare func(a, b Entry) int) Ordering {
+	return Ordering{compare: compare, kind: orderCompare}
+}
+
+// Order makes a group report its values in the given ordering.
+func Order(ordering Ordering) Option {
+	return func(cfg *config) {
+		cfg.order = ordering
+	}
+}
+
+// sortEntries returns entries in this ordering. seqs holds the submission index of every entry and
+// is only read by SubmissionOrder. entries is never modified; it is returned as is by
+// InsertionOrder and copied by the other orderings.
+func (o Ordering) sortEntries(entries []Entry, seqs []uint64) []Entry {
+	switch o.kind {
+	case orderSubmission:
+		indexes := make([]int, len(entries))
+		for i := range indexes {
+			indexes[i] = i
+		}
+
+		slices.SortStableFunc(indexes, func(a, b int) int {
+			return cmp.Compare(seqs[a], seqs[b])
+		})
+
+		sorted := make([]Entry, len(entries))
+		for i, index := range indexes {
+			sorted[i] = entries[index]
+		}
+
+		return sorted
+	case orderCompare:
+		sorted := slices.Clone(entries)
+		slices.SortStableFunc(sorted, o.compare)
+
+		return sorted
+	default:
+		return entries
+	}
+}
+
+// sortErrors returns errs in this ordering, see sortEntries.
+func (o Ordering) sortErrors(errs []error, seqs []uint64) []error {
+	if o.kind == orderInsertion {
+		return errs
+	}
+
+	entries := make([]Entry, len(errs))
+	for i, err := range errs {
+		entries[i] = Entry{Err: err}
+	}
+
+	sorted := make([]error, len(errs))
+	for i, entry := range o.sortEntries(entries, seqs) {
+		sorted[i] = entry.Err
+	}
+
+	return sorted
+}
+
+// compareStatusThenMessage orders entries by status and then by error message.
+func compareStatusThenMessage(a, b Entry) int {
+	if a.HasStatus != b.HasStatus {
+		if a.HasStatus {
+			return 1
+		}
+
+		return -1
+	}
+
+	if c := cmp.Compare(a.Status, b.Status); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(message(a.Err), message(b.Err))
+}
+
+// message returns the message of err, or the empty string if err is nil.
+func message(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,115 @@
+package error_group
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestOrder_Submission(t *testing.T) {
+	eg := NewErrorGroup(Order(SubmissionOrder))
+
+	numTasks := 5
+	turns := make([]chan struct{}, numTasks)
+	for i := range turns {
+		turns[i] = make(chan struct{})
+	}
+
+	// every task waits for the one submitted after it so they return in reverse order.
+	for i := 0; i < numTasks; i++ {
+		i := i
+		eg.Go(func() error {
+			if i < numTasks-1 {
+				<-turns[i+1]
+			}
+
+			defer close(turns[i])
+
+			return errors.New("task " + strconv.Itoa(i))
+		})
+	}
+
+	eg.Wait()
+
+	t.Run("verify errors are reported in submission order", func(t *testing.T) {
+		assert.Equal(t, "task 0\ntask 1\ntask 2\ntask 3\ntask 4", eg.Error())
+		assert.Equal(t, "task 0", eg.All()[0].Error())
+	})
+	t.Run("verify iteration keeps insertion order", func(t *testing.T) {
+		for _, err := range eg.Entries() {
+			assert.Equal(t, "task 4", err.Error())
+			break
+		}
+	})
+	t.Run("verify Filter() keeps the submission order", func(t *testing.T) {
+		filtered := eg.Filter(func(err error) bool {
+			return !strings.HasSuffix(err.Error(), "2")
+		})
+		assert.Equal(t, "task 0\ntask 1\ntask 3\ntask 4", filtered.Error())
+	})
+}
+
+func TestOrder_SubmissionStatusGroup(t *testing.T) {
+	esg := NewErrorStatusGroup(Order(SubmissionOrder))
+
+	second := make(chan struct{})
+	esg.Go(func() (int, error) {
+		<-second
+		return 500, errors.New("first submitted")
+	})
+	esg.Go(func() (int, error) {
+		defer close(second)
+		return 404, errors.New("second submitted")
+	})
+	esg.Wait()
+	esg.AddStatusAndError(503, errors.New("added directly"))
+
+	t.Run("verify entries are reported in submission order", func(t *testing.T) {
+		statuses, errs := esg.All()
+		assert.DeepEqual(t, []int{500, 404, 503}, statuses)
+		assert.Equal(t, "first submitted", errs[0].Error())
+	})
+}
+
+func TestOrder_StatusThenMessage(t *testing.T) {
+	esg := NewErrorStatusGroup(Order(StatusThenMessageOrder))
+
+	esg.AddStatusAndError(503, errors.New("b unavailable"))
+	esg.AddStatusAndError(404, errors.New("not found"))
+	esg.AddStatusAndError(503, errors.New("a unavailable"))
+	esg.AddError(errors.New("no status"))
+
+	t.Run("verify entries are sorted by status then message", func(t *testing.T) {
+		expected := []string{"no status", "not found", "a unavailable", "b unavailable"}
+		_, errs := esg.All()
+		assert.DeepEqual(t, expected, errorStrings(errs))
+		assert.True(t, strings.HasSuffix(esg.Error(), strings.Join(expected, "\n")))
+	})
+	t.Run("verify the JSON serialization is sorted", func(t *testing.T) {
+		data, err := json.Marshal(esg.Snapshot())
+		assert.Nil(t, err)
+		assert.True(t, strings.Contains(string(data), `"statuses":[404,503,503]`))
+	})
+}
+
+func TestOrder_OrderBy(t *testing.T) {
+	eg := NewErrorGroup(Order(OrderBy(func(a, b Entry) int {
+		return len(a.Err.Error()) - len(b.Err.Error())
+	})))
+
+	eg.Add(errors.New("longest"))
+	eg.Add(errors.New("short"))
+	eg.Add(errors.New("tiny"))
+	eg.Add(errors.New("small"))
+
+	t.Run("verify the comparator decides the order and ties keep insertion order", func(t *testing.T) {
+		assert.Equal(t, "tiny\nshort\nsmall\nlongest", eg.Error())
+	})
+	t.Run("verify First() and Last() keep insertion order", func(t *testing.T) {
+		assert.Equal(t, "longest", eg.First().Error())
+		assert.Equal(t, "small", eg.Last().Error())
+	})
+}
@@ -0,0 +1,119 @@
+// Package otelgroup traces the tasks of an error status group with OpenTelemetry.
+//
+// Every task started through a Group runs in its own child span of the span found in the context
+// the Group was created with. The errors returned by tasks are recorded as span events and set the
+// status of the task's span to Error. Wait annotates the parent span with the number of errors and
+// the aggregated status of the group:
+//
+//	esg := error_group.NewErrorStatusGroup()
+//	group := otelgroup.New(ctx, esg, otel.Tracer("search"))
+//
+//	group.Go("teacher.Search", func(ctx context.Context) (int, error) {
+//		return teacher.Search(ctx, query)
+//	})
+//
+//	status, err := group.Wait()
+package otelgroup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/seantcanavan/error_group"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ErrorCountKey is the attribute holding the number of errors of a finalized group.
+	ErrorCountKey = attribute.Key("error_group.error_count")
+	// OutcomeKey is the attribute holding the outcome of a finalized group.
+	OutcomeKey = attribute.Key("error_group.outcome")
+	// StatusCodeKey is the attribute holding the status of a task or the aggregated status of a group.
+	StatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// StatusGroup is the part of an error status group a Group relies on. It is satisfied by the
+// groups returned by error_group.NewErrorStatusGroup and error_group.NewErrorStatusGroupWithContext.
+type StatusGroup interface {
+	Go(f func() (int, error)) bool
+	GoOptional(f func() (int, error)) bool
+	LenErrors() int
+	Outcome() error_group.Outcome
+	Wait() (int, error)
+}
+
+// Group starts the tasks of an error status group in their own spans.
+type Group struct {
+	ctx    context.Context
+	group  StatusGroup
+	tracer trace.Tracer
+}
+
+// New returns a Group starting the tasks of group with tracer as children of the span held by ctx.
+// The context handed to every task is derived from ctx.
+func New(ctx context.Context, group StatusGroup, tracer trace.Tracer) *Group {
+	return &Group{
+		ctx:    ctx,
+		group:  group,
+		tracer: tracer,
+	}
+}
+
+// Go starts f with the error status group in a new span called name. See StatusGroup.Go.
+func (g *Group) Go(name string, f func(ctx context.Context) (int, error)) bool {
+	return g.group.Go(g.traced(name, f))
+}
+
+// GoOptional starts f with the error status group as an optional task in a new span called name.
+// See StatusGroup.GoOptional.
+func (g *Group) GoOptional(name string, f func(ctx context.Context) (int, error)) bool {
+	return g.group.GoOptional(g.traced(name, f))
+}
+
+// Wait waits for the error status group, annotates the parent span with the number of errors, the
+// outcome and the aggregated status of the group, and returns what the group's Wait returned. The
+// status of the parent span is set to Error when the group returned an error.
+func (g *Group) Wait() (int, error) {
+	status, err := g.group.Wait()
+
+	span := trace.SpanFromContext(g.ctx)
+	span.SetAttributes(
+		ErrorCountKey.Int(g.group.LenErrors()),
+		OutcomeKey.String(g.group.Outcome().String()),
+		StatusCodeKey.Int(status),
+	)
+
+	if err != nil {
+		span.SetStatus(codes.Error, fmt.Sprintf("%d errors", g.group.LenErrors()))
+	}
+
+	return status, err
+}
+
+// traced wraps f so that it runs in a new span called name.
+func (g *Group) traced(name string, f func(ctx context.Context) (int, error)) func() (int, error) {
+	return func() (int, error) {
+		ctx, span := g.tracer.Start(g.ctx, name)
+		defer span.End()
+
+		defer func() {
+			if value := recover(); value != nil {
+				span.SetStatus(codes.Error, fmt.Sprintf("panic: %v", value))
+				panic(value)
+			}
+		}()
+
+		status, err := f(ctx)
+
+		span.SetAttributes(StatusCodeKey.Int(status))
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return status, err
+	}
+}
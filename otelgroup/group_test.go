@@ -0,0 +1,83 @@
+package otelgroup
+
+import (
+	"context"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"github.com/seantcanavan/error_group"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("otelgroup_test")
+
+	ctx, parent := tracer.Start(context.Background(), "search")
+
+	group := New(ctx, error_group.NewErrorStatusGroup(), tracer)
+	group.Go("teacher.Search", func(ctx context.Context) (int, error) {
+		return 200, nil
+	})
+	group.Go("student.Search", func(ctx context.Context) (int, error) {
+		return 503, errors.New("students unavailable")
+	})
+	group.GoOptional("learner.Search", func(ctx context.Context) (int, error) {
+		panic("learners lookup panicked")
+	})
+
+	status, err := group.Wait()
+	parent.End()
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+
+	t.Run("verify every task runs in a child span of the parent", func(t *testing.T) {
+		assert.Equal(t, 4, len(spans))
+
+		for _, name := range []string{"teacher.Search", "student.Search", "learner.Search"} {
+			assert.Equal(t, parent.SpanContext().SpanID(), spans[name].Parent.SpanID())
+		}
+	})
+	t.Run("verify a successful task span is not marked as an error", func(t *testing.T) {
+		assert.Equal(t, codes.Unset, spans["teacher.Search"].Status.Code)
+		assert.Equal(t, 0, len(spans["teacher.Search"].Events))
+	})
+	t.Run("verify task errors become span events and set the status to Error", func(t *testing.T) {
+		span := spans["student.Search"]
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.Equal(t, "students unavailable", span.Status.Description)
+		assert.Equal(t, 1, len(span.Events))
+		assert.Equal(t, "exception", span.Events[0].Name)
+		assert.True(t, hasAttribute(span.Attributes, StatusCodeKey.Int(503)))
+	})
+	t.Run("verify a panicking task marks its span as an error", func(t *testing.T) {
+		assert.Equal(t, codes.Error, spans["learner.Search"].Status.Code)
+	})
+	t.Run("verify Wait() annotates the parent span", func(t *testing.T) {
+		span := spans["search"]
+		assert.Equal(t, 503, status)
+		assert.NotNil(t, err)
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.True(t, hasAttribute(span.Attributes, ErrorCountKey.Int(1)))
+		assert.True(t, hasAttribute(span.Attributes, StatusCodeKey.Int(503)))
+		assert.True(t, hasAttribute(span.Attributes, OutcomeKey.String("failure")))
+	})
+}
+
+// hasAttribute reports whether attributes contains expected.
+func hasAttribute(attributes []attribute.KeyValue, expected attribute.KeyValue) bool {
+	for _, attr := range attributes {
+		if attr == expected {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package error_group
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Outcome summarizes the results saved to an error status group, telling the results of required
+// work apart from those of optional work.
+type Outcome int
+
+const (
+	// OutcomeSuccess means every required and optional result succeeded.
+	OutcomeSuccess Outcome = iota
+	// OutcomePartialSuccess means every required result succeeded but at least one optional result
+	// failed. The response can be served, flagged as degraded.
+	OutcomePartialSuccess
+	// OutcomeFailure means at least one required result failed.
+	OutcomeFailure
+)
+
+// String fulfills the fmt.Stringer interface.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomePartialSuccess:
+		return "partial_success"
+	case OutcomeFailure:
+		return "failure"
+	default:
+		return "unknown"
+	}
+}
+
+// AddOptionalStatusAndError adds the status and error of an optional piece of work to this error
+// status group instance. Optional values are recorded but do not take part in the aggregate: the
+// status is not considered by HighestStatus, LowestStatus or LenStatuses, never trips the group and
+// does not run the hooks, and a non-nil error is saved as a warning. They are reported by
+// OptionalStatuses, Warnings and Outcome.
+func (esg *ErrorStatusGroup) AddOptionalStatusAndError(status int, err error) {
+	esg.record(0, []Entry{optionalEntry(Entry{Err: err, HasStatus: true, Status: status})}, nil)
+}
+
+// Degraded reports whether Outcome is OutcomePartialSuccess.
+func (esg *ErrorStatusGroup) Degraded() bool {
+	return esg.Outcome() == OutcomePartialSuccess
+}
+
+// GoOptional is like Go but records the status and error returned by f with
+// AddOptionalStatusAndError.
+func (esg *ErrorStatusGroup) GoOptional(f func() (int, error)) bool {
+	return esg.launch(true, func() Entry {
+		status, err := f()
+
+		return Entry{Err: err, HasStatus: true, Status: status}
+	})
+}
+
+// OptionalStatuses returns a new slice containing every optional status value saved to this error
+// status group instance.
+func (esg *ErrorStatusGroup) OptionalStatuses() []int {
+	return esg.Snapshot().OptionalStatuses()
+}
+
+// Outcome computes the outcome of the results saved to this error status group instance and its
+// child groups. A result fails when it carries an error or a status of 400 or above.
+func (esg *ErrorStatusGroup) Outcome() Outcome {
+	return esg.Snapshot().Outcome()
+}
+
+// Degraded reports whether Outcome is OutcomePartialSuccess.
+func (s StatusSnapshot) Degraded() bool {
+	return s.Outcome() == OutcomePartialSuccess
+}
+
+// OptionalStatuses returns a new slice containing every optional status value captured by this
+// snapshot, not counting those of child groups.
+func (s StatusSnapshot) OptionalStatuses() []int {
+	var statuses []int
+
+	for _, entry := range s.optionalEntries() {
+		if entry.HasStatus {
+			statuses = append(statuses, entry.Status)
+		}
+	}
+
+	return statuses
+}
+
+// Outcome computes the outcome of the results captured by this snapshot and its child snapshots.
+// A result fails when it carries an error or a status of 400 or above.
+func (s StatusSnapshot) Outcome() Outcome {
+	outcome := OutcomeSuccess
+
+	for _, entry := range s.entries {
+		if entry.failed() {
+			return OutcomeFailure
+		}
+	}
+
+	for _, entry := range s.optionalEntries() {
+		if entry.failed() {
+			outcome = OutcomePartialSuccess
+		}
+	}
+
+	for _, child := range s.groups {
+		if childOutcome := child.snapshot.Outcome(); childOutcome > outcome {
+			outcome = childOutcome
+		}
+	}
+
+	return outcome
+}
+
+// optionalEntries returns the optional entries captured by this snapshot: the successful ones
+// followed by the failed ones that were saved as warnings.
+func (s StatusSnapshot) optionalEntries() []Entry {
+	entries := slices.Clone(s.optional)
+
+	for _, entry := range s.warnings {
+		if entry.Optional {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// optionalEntry marks entry as optional. It keeps the severity of entry unless entry carries an
+// error, in which case it is downgraded to a warning.
+func optionalEntry(entry Entry) Entry {
+	entry.Optional = true
+
+	if entry.Err != nil {
+		entry.Severity = SeverityWarning
+	}
+
+	return entry
+}
+
+// failed reports whether entry describes a failed result.
+func (e Entry) failed() bool {
+	return e.Err != nil || (e.HasStatus && e.Status >= http.StatusBadRequest)
+}
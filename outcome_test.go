@@ -0,0 +1,128 @@
+package error_group
+
+import (
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"net/http"
+	"testing"
+)
+
+func TestErrorStatusGroup_AddOptionalStatusAndError(t *testing.T) {
+	esg := NewErrorStatusGroup(StatusThreshold(500))
+
+	esg.AddStatus(http.StatusOK)
+	esg.AddOptionalStatusAndError(http.StatusServiceUnavailable, errors.New("learners lookup failed"))
+
+	status, err := esg.ToStatusAndError()
+
+	t.Run("verify optional statuses are excluded from the aggregate", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, status)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, esg.LenStatuses())
+		assert.Equal(t, http.StatusOK, esg.HighestStatus())
+		assert.False(t, esg.Tripped())
+	})
+	t.Run("verify optional statuses are recorded", func(t *testing.T) {
+		assert.DeepEqual(t, []int{http.StatusServiceUnavailable}, esg.OptionalStatuses())
+	})
+	t.Run("verify optional errors are recorded as warnings", func(t *testing.T) {
+		warnings := esg.Warnings()
+		assert.Equal(t, 1, len(warnings))
+		assert.Equal(t, "learners lookup failed", warnings[0].Error())
+	})
+	t.Run("verify the outcome is a degraded partial success", func(t *testing.T) {
+		assert.Equal(t, OutcomePartialSuccess, esg.Outcome())
+		assert.True(t, esg.Degraded())
+	})
+}
+
+func TestErrorStatusGroup_Outcome(t *testing.T) {
+	t.Run("verify a group where everything succeeded is a success", func(t *testing.T) {
+		esg := NewErrorStatusGroup()
+		esg.AddStatus(http.StatusOK)
+		esg.AddOptionalStatusAndError(http.StatusOK, nil)
+
+		assert.Equal(t, OutcomeSuccess, esg.Outcome())
+		assert.False(t, esg.Degraded())
+	})
+	t.Run("verify a failed required result is a failure", func(t *testing.T) {
+		esg := NewErrorStatusGroup()
+		esg.AddStatus(http.StatusNotFound)
+		esg.AddOptionalStatusAndError(http.StatusInternalServerError, nil)
+
+		assert.Equal(t, OutcomeFailure, esg.Outcome())
+	})
+	t.Run("verify the outcome of child groups rolls up", func(t *testing.T) {
+		child := NewErrorStatusGroup()
+		child.AddOptionalStatusAndError(http.StatusGatewayTimeout, nil)
+
+		esg := NewErrorStatusGroup()
+		esg.AddStatus(http.StatusOK)
+		esg.AddGroup("learners", child)
+
+		assert.Equal(t, OutcomePartialSuccess, esg.Outcome())
+	})
+	t.Run("verify String() names every outcome", func(t *testing.T) {
+		assert.Equal(t, "success", OutcomeSuccess.String())
+		assert.Equal(t, "partial_success", OutcomePartialSuccess.String())
+		assert.Equal(t, "failure", OutcomeFailure.String())
+		assert.Equal(t, "unknown", Outcome(9).String())
+	})
+}
+
+func TestErrorStatusGroup_GoOptional(t *testing.T) {
+	esg := NewErrorStatusGroup()
+
+	esg.Go(func() (int, error) {
+		return http.StatusOK, nil
+	})
+	esg.GoOptional(func() (int, error) {
+		return http.StatusBadGateway, errors.New("learners lookup failed")
+	})
+
+	status, err := esg.Wait()
+
+	t.Run("verify the optional task does not fail the request", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, status)
+		assert.Nil(t, err)
+		assert.True(t, esg.Degraded())
+	})
+}
+
+func TestErrorStatusGroup_OptionalSeverity(t *testing.T) {
+	esg := NewErrorStatusGroup()
+	subscription := esg.Subscribe()
+
+	hooked := 0
+	esg.OnError(func(error) { hooked++ })
+	esg.OnStatus(func(int) { hooked++ })
+
+	esg.AddOptionalStatusAndError(http.StatusOK, nil)
+	esg.AddOptionalStatusAndError(http.StatusServiceUnavailable, errors.New("learners lookup failed"))
+
+	t.Run("verify a successful optional result keeps its severity", func(t *testing.T) {
+		entry := <-subscription
+		assert.True(t, entry.Optional)
+		assert.Equal(t, SeverityError, entry.Severity)
+		assert.Nil(t, entry.Err)
+	})
+	t.Run("verify a failed optional result is downgraded to a warning", func(t *testing.T) {
+		entry := <-subscription
+		assert.True(t, entry.Optional)
+		assert.Equal(t, SeverityWarning, entry.Severity)
+		assert.Equal(t, 1, len(esg.Warnings()))
+	})
+	t.Run("verify optional results do not run the hooks", func(t *testing.T) {
+		assert.Equal(t, 0, hooked)
+	})
+	t.Run("verify Partition() keeps the optional results", func(t *testing.T) {
+		failed, succeeded := esg.Partition(func(entry Entry) bool {
+			return entry.failed()
+		})
+
+		assert.DeepEqual(t, []int{http.StatusServiceUnavailable}, failed.OptionalStatuses())
+		assert.Equal(t, 1, len(failed.Warnings()))
+		assert.DeepEqual(t, []int{http.StatusOK}, succeeded.OptionalStatuses())
+		assert.Equal(t, 0, succeeded.LenStatuses())
+	})
+}
@@ -0,0 +1,46 @@
+package error_group
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is recorded in place of the result of a function started with Go, or one of its
+// variants, that panicked. The panic is recovered so that it does not crash the process.
+type PanicError struct {
+	Stack []byte
+	Value any
+}
+
+// Error fulfills the builtin.Error interface.
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", pe.Value)
+}
+
+// StatusCode returns the status value an error status group records for a PanicError.
+func (pe *PanicError) StatusCode() int {
+	return http.StatusInternalServerError
+}
+
+// Unwrap returns the value the function panicked with if it is an error.
+func (pe *PanicError) Unwrap() error {
+	if err, ok := pe.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// callProtected calls f and returns the entry it produces or, if f panics, an entry holding a
+// *PanicError.
+func callProtected(f func() Entry) (entry Entry) {
+	defer func() {
+		if value := recover(); value != nil {
+			panicError := &PanicError{Stack: debug.Stack(), Value: value}
+			entry = Entry{Err: panicError, HasStatus: true, Status: panicError.StatusCode()}
+		}
+	}()
+
+	return f()
+}
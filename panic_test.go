@@ -0,0 +1,40 @@
+package error_group
+
+import (
+	"context"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPanicError(t *testing.T) {
+	errCause := errors.New("cause")
+	esg := NewErrorStatusGroup()
+
+	esg.Go(func() (int, error) {
+		panic(errCause)
+	})
+	esg.GoWithTimeout("timed", time.Second, func(ctx context.Context) (int, error) {
+		var m map[string]int
+		m["boom"]++
+
+		return http.StatusOK, nil
+	})
+
+	status, _ := esg.Wait()
+
+	t.Run("verify panics are recorded with a 500 status", func(t *testing.T) {
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Equal(t, 2, esg.LenErrors())
+	})
+	t.Run("verify a PanicError unwraps to the error the function panicked with", func(t *testing.T) {
+		assert.NotNil(t, esg.Find(errCause))
+	})
+	t.Run("verify a PanicError carries the stack of the panic", func(t *testing.T) {
+		for _, panicError := range AsAll[*PanicError](esg) {
+			assert.True(t, len(panicError.Stack) > 0)
+		}
+	})
+}
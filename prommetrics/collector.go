@@ -0,0 +1,120 @@
+// Package prommetrics exports the reports of finalized error groups as Prometheus metrics.
+//
+// Register a Collector with a Prometheus registry and pass it to the groups it should observe:
+//
+//	collector := prommetrics.NewCollector("search")
+//	prometheus.MustRegister(collector)
+//
+//	esg := error_group.NewErrorStatusGroup(error_group.Observe(collector))
+package prommetrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/seantcanavan/error_group"
+)
+
+// Collector is a prometheus.Collector and an error_group.Observer. Every group it observes adds to
+// its metrics once, when the group is finalized.
+type Collector struct {
+	errorTypes   *prometheus.CounterVec
+	errors       prometheus.Counter
+	groups       *prometheus.CounterVec
+	panics       prometheus.Counter
+	statuses     *prometheus.CounterVec
+	taskDuration prometheus.Histogram
+	warnings     prometheus.Counter
+}
+
+// NewCollector returns a new Collector whose metric names are prefixed with namespace, which may be
+// empty.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		errorTypes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "error_group",
+			Name:      "error_types_total",
+			Help:      "Errors saved to finalized groups, by the dynamic type of the error.",
+		}, []string{"type"}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "error_group",
+			Name:      "errors_total",
+			Help:      "Errors saved to finalized groups.",
+		}),
+		groups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "error_group",
+			Name:      "finalized_total",
+			Help:      "Finalized groups, by outcome and whether they tripped a threshold.",
+		}, []string{"outcome", "tripped"}),
+		panics: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "error_group",
+			Name:      "panics_total",
+			Help:      "Functions started by finalized groups that panicked.",
+		}),
+		statuses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "error_group",
+			Name:      "statuses_total",
+			Help:      "Status values saved to finalized groups, by class.",
+		}, []string{"class"}),
+		taskDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "error_group",
+			Name:      "task_duration_seconds",
+			Help:      "Duration of the functions started by finalized groups, retries included.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		warnings: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "error_group",
+			Name:      "warnings_total",
+			Help:      "Warnings saved to finalized groups.",
+		}),
+	}
+}
+
+// Collect fulfills the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.errorTypes.Collect(ch)
+	c.errors.Collect(ch)
+	c.groups.Collect(ch)
+	c.panics.Collect(ch)
+	c.statuses.Collect(ch)
+	c.taskDuration.Collect(ch)
+	c.warnings.Collect(ch)
+}
+
+// Describe fulfills the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.errorTypes.Describe(ch)
+	c.errors.Describe(ch)
+	c.groups.Describe(ch)
+	c.panics.Describe(ch)
+	c.statuses.Describe(ch)
+	c.taskDuration.Describe(ch)
+	c.warnings.Describe(ch)
+}
+
+// Observe fulfills the error_group.Observer interface.
+func (c *Collector) Observe(report error_group.Report) {
+	c.errors.Add(float64(report.Errors))
+	c.panics.Add(float64(report.Panics))
+	c.warnings.Add(float64(report.Warnings))
+	c.groups.WithLabelValues(report.Outcome.String(), strconv.FormatBool(report.Tripped)).Inc()
+
+	for errorType, count := range report.ErrorTypes {
+		c.errorTypes.WithLabelValues(errorType).Add(float64(count))
+	}
+
+	for class, count := range report.Statuses.Classes {
+		c.statuses.WithLabelValues(class).Add(float64(count))
+	}
+
+	for _, duration := range report.TaskDurations {
+		c.taskDuration.Observe(duration.Seconds())
+	}
+}
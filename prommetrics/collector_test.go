@@ -0,0 +1,65 @@
+package prommetrics
+
+import (
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/seantcanavan/error_group"
+	"strings"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector("test")
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	esg := error_group.NewErrorStatusGroup(error_group.Observe(collector))
+	esg.Go(func() (int, error) {
+		return 200, nil
+	})
+	esg.Go(func() (int, error) {
+		return 503, errors.New("unavailable")
+	})
+	esg.GoOptional(func() (int, error) {
+		panic("learners lookup panicked")
+	})
+	esg.Wait()
+
+	t.Run("verify the collector passes the registry's consistency checks", func(t *testing.T) {
+		problems, err := testutil.CollectAndLint(collector)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(problems))
+	})
+	t.Run("verify errors, warnings and panics are counted", func(t *testing.T) {
+		assert.Equal(t, 1.0, testutil.ToFloat64(collector.errors))
+		assert.Equal(t, 1.0, testutil.ToFloat64(collector.warnings))
+		assert.Equal(t, 1.0, testutil.ToFloat64(collector.panics))
+	})
+	t.Run("verify statuses are counted by class", func(t *testing.T) {
+		expected := `
+# HELP test_error_group_statuses_total Status values saved to finalized groups, by class.
+# TYPE test_error_group_statuses_total counter
+test_error_group_statuses_total{class="2xx"} 1
+test_error_group_statuses_total{class="5xx"} 1
+`
+		assert.Nil(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "test_error_group_statuses_total"))
+	})
+	t.Run("verify the group is counted by outcome", func(t *testing.T) {
+		assert.Equal(t, 1.0, testutil.ToFloat64(collector.groups.WithLabelValues("failure", "false")))
+	})
+	t.Run("verify every task duration is observed", func(t *testing.T) {
+		families, err := registry.Gather()
+		assert.Nil(t, err)
+
+		var samples uint64
+		for _, family := range families {
+			if family.GetName() == "test_error_group_task_duration_seconds" {
+				samples = family.GetMetric()[0].GetHistogram().GetSampleCount()
+			}
+		}
+
+		assert.Equal(t, uint64(3), samples)
+	})
+}
@@ -0,0 +1,224 @@
+package error_group
+
+import (
+	"errors"
+	"slices"
+)
+
+// AsAll returns every error in the tree of err that can be assigned to T, in the order they were
+// added. Groups and snapshots are traversed through their Unwrap method, so passing a group
+// searches a consistent snapshot of the group and all of its child groups. Errors that implement
+// an As(any) bool method are matched the same way errors.As matches them.
+func AsAll[T any](err error) []T {
+	var matches []T
+
+	var walk func(error)
+	walk = func(current error) {
+		if current == nil {
+			return
+		}
+
+		if match, ok := current.(T); ok {
+			matches = append(matches, match)
+		} else if matcher, ok := current.(interface{ As(any) bool }); ok {
+			var match T
+			if matcher.As(&match) {
+				matches = append(matches, match)
+			}
+		}
+
+		switch wrapper := current.(type) {
+		case interface{ Unwrap() []error }:
+			for _, wrapped := range wrapper.Unwrap() {
+				walk(wrapped)
+			}
+		case interface{ Unwrap() error }:
+			walk(wrapper.Unwrap())
+		}
+	}
+
+	walk(err)
+
+	return matches
+}
+
+// Filter returns a new error group instance, configured with the same options as this one except
+// for Observe and Register, that contains every error saved to this error group instance for which
+// pred returns true, including warnings. Errors of child groups are not considered.
+func (eg *ErrorGroup) Filter(pred func(error) bool) *ErrorGroup {
+	matched, _ := eg.Partition(pred)
+
+	return matched
+}
+
+// Find returns the first error saved to this error group instance or any of its child groups for
+// which errors.Is reports a match with target. It returns nil when no error matches.
+func (eg *ErrorGroup) Find(target error) error {
+	return eg.Snapshot().Find(target)
+}
+
+// Partition splits the errors saved to this error group instance into two new error group
+// instances: one with every error for which pred returns true and one with the rest. Both are
+// configured with the same options as this one except for Observe and Register. Warnings are split
+// the same way and stay warnings. Errors of child groups are not considered.
+func (eg *ErrorGroup) Partition(pred func(error) bool) (*ErrorGroup, *ErrorGroup) {
+	eg.mutex.Lock()
+	cfg := eg.config
+	cfg.observers = nil
+	cfg.registry = nil
+	errs := eg.errors[:len(eg.errors):len(eg.errors)]
+	seqs := eg.seqs[:len(eg.seqs):len(eg.seqs)]
+	warnings := eg.warnings[:len(eg.warnings):len(eg.warnings)]
+	eg.mutex.Unlock()
+
+	matched := NewErrorGroup()
+	matched.config = cfg
+	unmatched := NewErrorGroup()
+	unmatched.config = cfg
+
+	for i, currentError := range errs {
+		target := unmatched
+		if pred(currentError) {
+			target = matched
+		}
+
+		target.errors = append(target.errors, currentError)
+
+		if cfg.order.kind == orderSubmission {
+			target.seqs = append(target.seqs, seqs[i])
+		}
+	}
+
+	for _, warning := range warnings {
+		target := unmatched
+		if pred(warning) {
+			target = matched
+		}
+
+		target.warnings = append(target.warnings, warning)
+	}
+
+	return matched, unmatched
+}
+
+// Find returns the first error captured by this snapshot or any of its child snapshots for which
+// errors.Is reports a match with target. It returns nil when no error matches.
+func (s Snapshot) Find(target error) error {
+	for _, currentError := range s.errors {
+		if errors.Is(currentError, target) {
+			return currentError
+		}
+	}
+
+	for _, child := range s.groups {
+		if found := child.snapshot.Find(target); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// Filter returns a new error status group instance, configured with the same options as this one
+// except for Observe and Register, that contains every entry saved to this error status group
+// instance for which pred returns true, including warnings and optional entries. Entries of child
+// groups are not considered.
+func (esg *ErrorStatusGroup) Filter(pred func(Entry) bool) *ErrorStatusGroup {
+	matched, _ := esg.Partition(pred)
+
+	return matched
+}
+
+// Find returns the first error saved to this error status group instance or any of its child groups
+// for which errors.Is reports a match with target. It returns nil when no error matches.
+func (esg *ErrorStatusGroup) Find(target error) error {
+	return esg.Snapshot().Find(target)
+}
+
+// HasStatus reports whether pred returns true for any status value saved to this error status group
+// instance or any of its child groups.
+func (esg *ErrorStatusGroup) HasStatus(pred func(int) bool) bool {
+	return esg.Snapshot().HasStatus(pred)
+}
+
+// Partition splits the entries saved to this error status group instance into two new error status
+// group instances: one with every entry for which pred returns true and one with the rest. Both
+// are configured with the same options as this one except for Observe and Register. Warnings and
+// optional entries are split the same way and stay warnings and optional entries. Entries of child
+// groups are not considered.
+func (esg *ErrorStatusGroup) Partition(pred func(Entry) bool) (*ErrorStatusGroup, *ErrorStatusGroup) {
+	esg.mutex.Lock()
+	cfg := esg.config
+	cfg.observers = nil
+	cfg.registry = nil
+	entries := esg.entries[:len(esg.entries):len(esg.entries)]
+	optional := esg.optional[:len(esg.optional):len(esg.optional)]
+	seqs := esg.seqs[:len(esg.seqs):len(esg.seqs)]
+	warnings := esg.warnings[:len(esg.warnings):len(esg.warnings)]
+	esg.mutex.Unlock()
+
+	matched := NewErrorStatusGroup()
+	matched.config = cfg
+	unmatched := NewErrorStatusGroup()
+	unmatched.config = cfg
+
+	for i, entry := range entries {
+		target := unmatched
+		if pred(entry) {
+			target = matched
+		}
+
+		target.addEntryLocked(entry)
+
+		if cfg.order.kind == orderSubmission {
+			target.seqs = append(target.seqs, seqs[i])
+		}
+	}
+
+	for _, entry := range slices.Concat(optional, warnings) {
+		target := unmatched
+		if pred(entry) {
+			target = matched
+		}
+
+		target.storeLocked(entry)
+	}
+
+	return matched, unmatched
+}
+
+// Find returns the first error captured by this snapshot or any of its child snapshots for which
+// errors.Is reports a match with target. It returns nil when no error matches.
+func (s StatusSnapshot) Find(target error) error {
+	for _, entry := range s.entries {
+		if entry.Err != nil && errors.Is(entry.Err, target) {
+			return entry.Err
+		}
+	}
+
+	for _, child := range s.groups {
+		if found := child.snapshot.Find(target); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// HasStatus reports whether pred returns true for any status value captured by this snapshot or any
+// of its child snapshots.
+func (s StatusSnapshot) HasStatus(pred func(int) bool) bool {
+	for _, entry := range s.entries {
+		if entry.HasStatus && pred(entry.Status) {
+			return true
+		}
+	}
+
+	for _, child := range s.groups {
+		if child.snapshot.HasStatus(pred) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+package error_group
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jgroeneveld/trial/assert"
+	"testing"
+)
+
+var errRetryable = errors.New("retryable")
+
+type statusError struct {
+	status int
+}
+
+func (se *statusError) Error() string {
+	return fmt.Sprintf("status error [%d]", se.status)
+}
+
+func TestAsAll(t *testing.T) {
+	child := NewErrorStatusGroup()
+	child.AddStatusAndError(502, &statusError{status: 502})
+
+	esg := NewErrorStatusGroup()
+	esg.AddStatusAndError(503, fmt.Errorf("wrapped: %w", &statusError{status: 503}))
+	esg.AddError(errors.New("plain"))
+	esg.AddGroup("child", child)
+
+	t.Run("verify AsAll() returns every matching error in the hierarchy", func(t *testing.T) {
+		matches := AsAll[*statusError](esg)
+		assert.Equal(t, 2, len(matches))
+		assert.Equal(t, 503, matches[0].status)
+		assert.Equal(t, 502, matches[1].status)
+	})
+	t.Run("verify AsAll() returns nil when nothing matches", func(t *testing.T) {
+		assert.Equal(t, 0, len(AsAll[*statusError](NewErrorGroup())))
+	})
+}
+
+func TestErrorGroup_Filter(t *testing.T) {
+	eg := NewErrorGroup()
+	eg.Add(fmt.Errorf("first: %w", errRetryable))
+	eg.Add(errors.New("second"))
+	eg.Add(fmt.Errorf("third: %w", errRetryable))
+
+	isRetryable := func(err error) bool {
+		return errors.Is(err, errRetryable)
+	}
+
+	t.Run("verify Filter() returns a new group with the matching errors", func(t *testing.T) {
+		filtered := eg.Filter(isRetryable)
+		assert.Equal(t, 2, filtered.Len())
+		assert.Equal(t, "first: retryable\nthird: retryable", filtered.Error())
+		assert.Equal(t, 3, eg.Len())
+	})
+	t.Run("verify Partition() splits the errors into two groups", func(t *testing.T) {
+		matched, unmatched := eg.Partition(isRetryable)
+		assert.Equal(t, 2, matched.Len())
+		assert.Equal(t, 1, unmatched.Len())
+		assert.Equal(t, "second", unmatched.Error())
+	})
+	t.Run("verify Partition() splits the warnings and keeps them as warnings", func(t *testing.T) {
+		warned := NewErrorGroup()
+		warned.Add(errors.New("second"))
+		warned.AddWarning(fmt.Errorf("cache miss: %w", errRetryable))
+
+		matched, unmatched := warned.Partition(isRetryable)
+		assert.Equal(t, 0, matched.Len())
+		assert.Equal(t, 1, len(matched.Warnings()))
+		assert.Equal(t, 1, unmatched.Len())
+		assert.Equal(t, 0, len(unmatched.Warnings()))
+	})
+	t.Run("verify Find() returns the first error matching the target", func(t *testing.T) {
+		assert.Equal(t, "first: retryable", eg.Find(errRetryable).Error())
+		assert.Nil(t, eg.Find(errors.New("missing")))
+	})
+	t.Run("verify Find() searches child groups", func(t *testing.T) {
+		sentinel := errors.New("sentinel")
+		child := NewErrorGroup()
+		child.Add(sentinel)
+
+		parent := NewErrorGroup()
+		parent.AddGroup("child", child)
+		assert.Equal(t, sentinel, parent.Find(sentinel))
+	})
+}
+
+func TestErrorStatusGroup_Filter(t *testing.T) {
+	esg := NewErrorStatusGroup()
+	esg.AddStatusAndError(503, fmt.Errorf("first: %w", errRetryable))
+	esg.AddStatusAndError(404, errors.New("second"))
+	esg.AddStatus(200)
+
+	isRetryable := func(entry Entry) bool {
+		return errors.Is(entry.Err, errRetryable)
+	}
+
+	t.Run("verify Filter() keeps statuses paired with their errors", func(t *testing.T) {
+		filtered := esg.Filter(isRetryable)
+		assert.Equal(t, 1, filtered.LenErrors())
+		assert.Equal(t, 1, filtered.LenStatuses())
+		assert.Equal(t, 503, filtered.HighestStatus())
+	})
+	t.Run("verify Partition() splits the entries into two groups", func(t *testing.T) {
+		matched, unmatched := esg.Partition(isRetryable)
+		assert.Equal(t, 1, matched.LenStatuses())
+		assert.Equal(t, 2, unmatched.LenStatuses())
+		assert.Equal(t, 404, unmatched.HighestStatus())
+	})
+	t.Run("verify Find() returns the first error matching the target", func(t *testing.T) {
+		assert.Equal(t, "first: retryable", esg.Find(errRetryable).Error())
+	})
+	t.Run("verify HasStatus() reports whether any status matches", func(t *testing.T) {
+		assert.True(t, esg.HasStatus(func(status int) bool { return status >= 500 }))
+		assert.False(t, esg.HasStatus(func(status int) bool { return status == 418 }))
+	})
+	t.Run("verify HasStatus() searches child groups", func(t *testing.T) {
+		child := NewErrorStatusGroup()
+		child.AddStatus(418)
+
+		parent := NewErrorStatusGroup()
+		parent.AddGroup("child", child)
+		assert.True(t, parent.HasStatus(func(status int) bool { return status == 418 }))
+	})
+}
@@ -0,0 +1,172 @@
+package error_group
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// latestErrorsLimit is the number of errors a GroupStatus lists.
+const latestErrorsLimit = 10
+
+// Registry keeps track of live groups so that they can be inspected while they are in use, e.g.
+// through its http.Handler or an expvar variable published with the expvarregistry package. A
+// group joins a registry when it is created with Register and leaves it when it is closed. A
+// Registry is safe for concurrent use.
+type Registry struct {
+	groups map[registrant]string
+	mutex  sync.Mutex
+}
+
+// registrant is a group that can be added to a Registry.
+type registrant interface {
+	groupStatus(name string) GroupStatus
+}
+
+// GroupStatus describes the state of a live group at the time it was inspected. The status fields
+// are only set for error status groups.
+type GroupStatus struct {
+	Name          string   `json:"name"`
+	Kind          string   `json:"kind"`
+	Errors        int      `json:"errors"`
+	Warnings      int      `json:"warnings"`
+	Statuses      int      `json:"statuses,omitempty"`
+	HighestStatus int      `json:"highest_status,omitempty"`
+	LowestStatus  int      `json:"lowest_status,omitempty"`
+	LatestErrors  []string `json:"latest_errors"`
+	RunningTasks  int      `json:"running_tasks"`
+	Tripped       bool     `json:"tripped"`
+}
+
+// DefaultRegistry is a Registry ready to be used by groups created with Register(DefaultRegistry,
+// name). It is not published or served anywhere unless ServeHTTP or expvarregistry.Publish is
+// used.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{groups: map[registrant]string{}}
+}
+
+// Register adds a group to registry under name when the group is created and removes it when the
+// group is closed. Several groups may share the same name. The registry holds a reference to the
+// group until then, so a group that is never closed stays in the registry, and in memory, forever.
+// Close every registered group once it is no longer used.
+func Register(registry *Registry, name string) Option {
+	return func(cfg *config) {
+		cfg.name = name
+		cfg.registry = registry
+	}
+}
+
+// ServeHTTP fulfills the http.Handler interface and writes the status of every group in this
+// registry as a JSON array.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(r.Statuses()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Statuses inspects every group in this registry and returns their status ordered by name.
+func (r *Registry) Statuses() []GroupStatus {
+	r.mutex.Lock()
+	groups := make(map[registrant]string, len(r.groups))
+	for group, name := range r.groups {
+		groups[group] = name
+	}
+	r.mutex.Unlock()
+
+	statuses := make([]GroupStatus, 0, len(groups))
+	for group, name := range groups {
+		statuses = append(statuses, group.groupStatus(name))
+	}
+
+	slices.SortFunc(statuses, func(a, b GroupStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return statuses
+}
+
+// add adds group to this registry under name.
+func (r *Registry) add(group registrant, name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.groups[group] = name
+}
+
+// remove removes group from this registry.
+func (r *Registry) remove(group registrant) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.groups, group)
+}
+
+// RunningTasks returns the number of functions started with Go, or one of its variants, that
+// have not returned yet.
+func (eg *ErrorGroup) RunningTasks() int {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	return eg.running
+}
+
+// groupStatus fulfills the registrant interface.
+func (eg *ErrorGroup) groupStatus(name string) GroupStatus {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	return GroupStatus{
+		Name:         name,
+		Kind:         "error_group",
+		Errors:       len(eg.errors),
+		Warnings:     len(eg.warnings),
+		LatestErrors: errorStrings(eg.errors[max(0, len(eg.errors)-latestErrorsLimit):]),
+		RunningTasks: eg.running,
+		Tripped:      eg.tripReason != "",
+	}
+}
+
+// RunningTasks returns the number of functions started with Go, or one of its variants, that
+// have not returned yet.
+func (esg *ErrorStatusGroup) RunningTasks() int {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	return esg.running
+}
+
+// groupStatus fulfills the registrant interface.
+func (esg *ErrorStatusGroup) groupStatus(name string) GroupStatus {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	var latestErrors []error
+	for i := len(esg.entries) - 1; i >= 0 && len(latestErrors) < latestErrorsLimit; i-- {
+		if esg.entries[i].Err != nil {
+			latestErrors = append(latestErrors, esg.entries[i].Err)
+		}
+	}
+
+	slices.Reverse(latestErrors)
+	lowestStatus, highestStatus := esg.statusRangeLocked()
+
+	return GroupStatus{
+		Name:          name,
+		Kind:          "error_status_group",
+		Errors:        esg.errorCount,
+		Warnings:      len(esg.warnings),
+		Statuses:      esg.statusCount,
+		HighestStatus: highestStatus,
+		LowestStatus:  lowestStatus,
+		LatestErrors:  errorStrings(latestErrors),
+		RunningTasks:  esg.running,
+		Tripped:       esg.tripReason != "",
+	}
+}
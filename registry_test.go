@@ -0,0 +1,80 @@
+package error_group
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	eg := NewErrorGroup(Register(registry, "batch"))
+	for i := 0; i < latestErrorsLimit+2; i++ {
+		eg.Add(errors.New("error " + strconv.Itoa(i)))
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	esg := NewErrorStatusGroup(Register(registry, "search"))
+	esg.AddStatusAndError(503, errors.New("unavailable"))
+	esg.Go(func() (int, error) {
+		close(started)
+		<-release
+		return 200, nil
+	})
+	<-started
+
+	NewErrorGroup()
+
+	t.Run("verify Statuses() lists every registered group by name", func(t *testing.T) {
+		statuses := registry.Statuses()
+		assert.Equal(t, 2, len(statuses))
+		assert.Equal(t, "batch", statuses[0].Name)
+		assert.Equal(t, "search", statuses[1].Name)
+	})
+	t.Run("verify the status of an error group lists its latest errors", func(t *testing.T) {
+		status := registry.Statuses()[0]
+		assert.Equal(t, "error_group", status.Kind)
+		assert.Equal(t, latestErrorsLimit+2, status.Errors)
+		assert.Equal(t, latestErrorsLimit, len(status.LatestErrors))
+		assert.Equal(t, "error 2", status.LatestErrors[0])
+		assert.Equal(t, "error 11", status.LatestErrors[latestErrorsLimit-1])
+	})
+	t.Run("verify the status of an error status group includes statuses and running tasks", func(t *testing.T) {
+		status := registry.Statuses()[1]
+		assert.Equal(t, "error_status_group", status.Kind)
+		assert.Equal(t, 503, status.HighestStatus)
+		assert.Equal(t, 200, status.LowestStatus)
+		assert.Equal(t, 1, status.RunningTasks)
+		assert.DeepEqual(t, []string{"unavailable"}, status.LatestErrors)
+	})
+	t.Run("verify ServeHTTP() writes the statuses as JSON", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/groups", nil))
+
+		var statuses []GroupStatus
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &statuses))
+		assert.Equal(t, 2, len(statuses))
+	})
+
+	close(release)
+	esg.Wait()
+
+	t.Run("verify RunningTasks() drops once tasks return", func(t *testing.T) {
+		assert.Equal(t, 0, esg.RunningTasks())
+	})
+	t.Run("verify Close() removes a group from the registry and Reset() adds it back", func(t *testing.T) {
+		eg.Close()
+		assert.Equal(t, 1, len(registry.Statuses()))
+
+		eg.Reset()
+		assert.Equal(t, 2, len(registry.Statuses()))
+	})
+}
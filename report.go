@@ -0,0 +1,159 @@
+package error_group
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Observer is notified with a Report when a group is finalized, i.e. the first time Wait,
+// ToError or ToStatusAndError is called on it. Observers are called synchronously by the
+// goroutine finalizing the group, once per group, in registration order.
+type Observer interface {
+	Observe(report Report)
+}
+
+// Report summarizes a finalized group and its child groups.
+type Report struct {
+	// Errors is the number of errors saved to the group.
+	Errors int
+	// ErrorTypes counts the errors saved to the group by their dynamic type, e.g. "*errors.errorString".
+	ErrorTypes map[string]int
+	// Outcome is the outcome of the group. An error group fails when it holds an error and partially
+	// succeeds when it only holds warnings.
+	Outcome Outcome
+	// Panics is the number of functions started by the group that panicked.
+	Panics int
+	// Statuses counts the status values saved to an error status group. It is empty for an error group.
+	Statuses StatusHistogram
+	// TaskDurations holds how long every function started by the group took, retries included.
+	TaskDurations []time.Duration
+	// Tripped reports whether the group had crossed one of its thresholds.
+	Tripped bool
+	// Warnings is the number of warnings saved to the group.
+	Warnings int
+}
+
+// Observe registers observer to be notified with a Report when the group is finalized.
+func Observe(observer Observer) Option {
+	return func(cfg *config) {
+		cfg.observers = append(cfg.observers, observer)
+	}
+}
+
+// taskStats holds the statistics a group keeps about the functions it started.
+type taskStats struct {
+	durations []time.Duration
+	finalized bool
+	panics    int
+}
+
+// finishLocked records a function that ran for duration and whether entry reports a panic.
+func (ts *taskStats) finishLocked(duration time.Duration, entry Entry) {
+	ts.durations = append(ts.durations, duration)
+
+	var panicError *PanicError
+	if entry.Err != nil && errors.As(entry.Err, &panicError) {
+		ts.panics++
+	}
+}
+
+// finalize notifies the observers of this error group instance with a report built from snapshot
+// unless the group has already been finalized.
+func (eg *ErrorGroup) finalize(snapshot Snapshot) {
+	if len(eg.config.observers) == 0 {
+		return
+	}
+
+	eg.mutex.Lock()
+	if eg.stats.finalized {
+		eg.mutex.Unlock()
+		return
+	}
+
+	eg.stats.finalized = true
+	report := Report{
+		Panics:        eg.stats.panics,
+		TaskDurations: eg.stats.durations[:len(eg.stats.durations):len(eg.stats.durations)],
+		Tripped:       snapshot.Tripped(),
+	}
+	eg.mutex.Unlock()
+
+	report.ErrorTypes = map[string]int{}
+	snapshot.countErrors(&report)
+
+	if report.Errors > 0 {
+		report.Outcome = OutcomeFailure
+	} else if report.Warnings > 0 {
+		report.Outcome = OutcomePartialSuccess
+	}
+
+	for _, observer := range eg.config.observers {
+		observer.Observe(report)
+	}
+}
+
+// finalize notifies the observers of this error status group instance with a report built from
+// snapshot unless the group has already been finalized.
+func (esg *ErrorStatusGroup) finalize(snapshot StatusSnapshot) {
+	if len(esg.config.observers) == 0 {
+		return
+	}
+
+	esg.mutex.Lock()
+	if esg.stats.finalized {
+		esg.mutex.Unlock()
+		return
+	}
+
+	esg.stats.finalized = true
+	report := Report{
+		Panics:        esg.stats.panics,
+		TaskDurations: esg.stats.durations[:len(esg.stats.durations):len(esg.stats.durations)],
+		Tripped:       snapshot.Tripped(),
+	}
+	esg.mutex.Unlock()
+
+	report.ErrorTypes = map[string]int{}
+	snapshot.countErrors(&report)
+	report.Outcome = snapshot.Outcome()
+	report.Statuses = snapshot.StatusHistogram()
+
+	for _, observer := range esg.config.observers {
+		observer.Observe(report)
+	}
+}
+
+// countErrors adds the errors and warnings of this snapshot and its child snapshots to report.
+func (s Snapshot) countErrors(report *Report) {
+	for _, currentError := range s.errors {
+		report.Errors++
+		report.ErrorTypes[fmt.Sprintf("%T", currentError)]++
+	}
+
+	report.Warnings += len(s.warnings)
+
+	for _, child := range s.groups {
+		child.snapshot.countErrors(report)
+	}
+}
+
+// countErrors adds the errors and warnings of this snapshot and its child snapshots to report.
+func (s StatusSnapshot) countErrors(report *Report) {
+	for _, entry := range s.entries {
+		if entry.Err != nil {
+			report.Errors++
+			report.ErrorTypes[fmt.Sprintf("%T", entry.Err)]++
+		}
+	}
+
+	for _, entry := range s.warnings {
+		if entry.Err != nil {
+			report.Warnings++
+		}
+	}
+
+	for _, child := range s.groups {
+		child.snapshot.countErrors(report)
+	}
+}
@@ -0,0 +1,101 @@
+package error_group
+
+import (
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"os"
+	"sync"
+	"testing"
+)
+
+// recordingObserver is an Observer that keeps every report it is notified with.
+type recordingObserver struct {
+	mutex   sync.Mutex
+	reports []Report
+}
+
+func (ro *recordingObserver) Observe(report Report) {
+	ro.mutex.Lock()
+	defer ro.mutex.Unlock()
+
+	ro.reports = append(ro.reports, report)
+}
+
+func TestErrorStatusGroup_Observe(t *testing.T) {
+	observer := &recordingObserver{}
+	esg := NewErrorStatusGroup(Observe(observer))
+
+	child := NewErrorStatusGroup()
+	child.AddStatusAndError(404, os.ErrNotExist)
+
+	esg.AddGroup("child", child)
+	esg.Go(func() (int, error) {
+		return 200, nil
+	})
+	esg.Go(func() (int, error) {
+		return 503, errors.New("unavailable")
+	})
+	esg.GoOptional(func() (int, error) {
+		return 502, errors.New("learners unavailable")
+	})
+
+	esg.Wait()
+	esg.ToStatusAndError()
+
+	t.Run("verify the group is reported once when it is finalized", func(t *testing.T) {
+		assert.Equal(t, 1, len(observer.reports))
+	})
+
+	report := observer.reports[0]
+
+	t.Run("verify the report counts the errors of the hierarchy by type", func(t *testing.T) {
+		assert.Equal(t, 2, report.Errors)
+		assert.Equal(t, 1, report.Warnings)
+		assert.DeepEqual(t, map[string]int{"*errors.errorString": 2}, report.ErrorTypes)
+	})
+	t.Run("verify the report counts the statuses", func(t *testing.T) {
+		assert.Equal(t, 3, report.Statuses.Total)
+		assert.Equal(t, 1, report.Statuses.Classes["5xx"])
+	})
+	t.Run("verify the report holds the duration of every task", func(t *testing.T) {
+		assert.Equal(t, 3, len(report.TaskDurations))
+		assert.Equal(t, 0, report.Panics)
+	})
+	t.Run("verify the report holds the outcome", func(t *testing.T) {
+		assert.Equal(t, OutcomeFailure, report.Outcome)
+		assert.False(t, report.Tripped)
+	})
+	t.Run("verify Reset() allows the group to be reported again", func(t *testing.T) {
+		esg.Reset()
+		esg.ToError()
+		assert.Equal(t, 2, len(observer.reports))
+		assert.Equal(t, OutcomeSuccess, observer.reports[1].Outcome)
+	})
+}
+
+func TestErrorGroup_Observe(t *testing.T) {
+	observer := &recordingObserver{}
+	eg := NewErrorGroup(Observe(observer))
+
+	eg.AddWarning(errors.New("warning message"))
+	eg.Go(func() error {
+		panic("boom")
+	})
+
+	err := eg.Wait()
+
+	t.Run("verify a panic is recovered and recorded as a PanicError", func(t *testing.T) {
+		assert.Equal(t, "panic: boom", err.Error())
+		assert.Equal(t, 1, len(AsAll[*PanicError](eg)))
+	})
+	t.Run("verify the report counts the panic", func(t *testing.T) {
+		assert.Equal(t, 1, len(observer.reports))
+		assert.Equal(t, 1, observer.reports[0].Panics)
+		assert.Equal(t, 1, observer.reports[0].ErrorTypes["*error_group.PanicError"])
+		assert.Equal(t, OutcomeFailure, observer.reports[0].Outcome)
+	})
+	t.Run("verify Filter() does not report to the observers", func(t *testing.T) {
+		eg.Filter(func(error) bool { return true }).ToError()
+		assert.Equal(t, 1, len(observer.reports))
+	})
+}
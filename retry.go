@@ -0,0 +1,177 @@
+package error_group
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy describes how a group retries the functions started with Go.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values of 1 or less
+	// disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts. Zero means no cap.
+	MaxDelay time.Duration
+	// Multiplier is the factor the delay grows by after every attempt. Zero means 2.
+	Multiplier float64
+	// Jitter is the fraction, between 0 and 1, of every delay that is randomly removed so that
+	// concurrent tasks do not retry in lockstep.
+	Jitter float64
+	// RetryStatuses restricts retries to attempts that returned one of these status values. It is
+	// only used by error status groups.
+	RetryStatuses []int
+	// RetryErrors restricts retries to attempts whose error matches one of these errors according
+	// to errors.Is.
+	RetryErrors []error
+}
+
+// Retry makes a group retry the functions started with Go according to policy. When neither
+// RetryStatuses nor RetryErrors are set every attempt that returns an error is retried.
+func Retry(policy RetryPolicy) Option {
+	return func(cfg *config) {
+		cfg.retry = &policy
+	}
+}
+
+// RetryError is recorded in place of the error returned by a function started with Go when it
+// still failed after being retried. It carries every attempt in the order they happened.
+type RetryError struct {
+	Attempts []Entry
+}
+
+// Error fulfills the builtin.Error interface and describes every attempt on a single line.
+func (re *RetryError) Error() string {
+	sb := strings.Builder{}
+
+	sb.WriteString(strconv.Itoa(len(re.Attempts)))
+	sb.WriteString(" attempts failed")
+
+	for i, attempt := range re.Attempts {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString("; ")
+		}
+
+		sb.WriteString("attempt ")
+		sb.WriteString(strconv.Itoa(i + 1))
+		sb.WriteString(":")
+
+		if attempt.HasStatus {
+			sb.WriteString(" [")
+			sb.WriteString(strconv.Itoa(attempt.Status))
+			sb.WriteString("]")
+		}
+
+		if attempt.Err != nil {
+			sb.WriteString(" ")
+			sb.WriteString(attempt.Err.Error())
+		}
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the error of every attempt so that errors.Is and errors.As can match them.
+func (re *RetryError) Unwrap() []error {
+	errs := make([]error, 0, len(re.Attempts))
+
+	for _, attempt := range re.Attempts {
+		if attempt.Err != nil {
+			errs = append(errs, attempt.Err)
+		}
+	}
+
+	return errs
+}
+
+// delay returns how long to wait after the given number of failed attempts.
+func (rp RetryPolicy) delay(failures int) time.Duration {
+	multiplier := rp.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	delay := float64(rp.BaseDelay) * math.Pow(multiplier, float64(failures-1))
+	if rp.MaxDelay > 0 && delay > float64(rp.MaxDelay) {
+		delay = float64(rp.MaxDelay)
+	}
+
+	if rp.Jitter > 0 {
+		delay -= delay * rp.Jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// shouldRetry reports whether the attempt that produced entry should be retried.
+func (rp RetryPolicy) shouldRetry(entry Entry) bool {
+	if len(rp.RetryStatuses) == 0 && len(rp.RetryErrors) == 0 {
+		return entry.Err != nil
+	}
+
+	if entry.HasStatus && slices.Contains(rp.RetryStatuses, entry.Status) {
+		return true
+	}
+
+	for _, target := range rp.RetryErrors {
+		if entry.Err != nil && errors.Is(entry.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runAttempts calls attempt, retrying it according to the configured retry policy, and returns the
+// entry to record. The entry of a task that still failed after being retried carries a *RetryError.
+// Retrying stops early once ctx is done.
+func (cfg config) runAttempts(ctx context.Context, attempt func() Entry) Entry {
+	entry := attempt()
+
+	if cfg.retry == nil {
+		return entry
+	}
+
+	attempts := []Entry{entry}
+
+	for len(attempts) < cfg.retry.MaxAttempts && cfg.retry.shouldRetry(entry) {
+		if !cfg.sleep(ctx, cfg.retry.delay(len(attempts))) {
+			break
+		}
+
+		entry = attempt()
+		attempts = append(attempts, entry)
+	}
+
+	if len(attempts) == 1 || entry.Err == nil {
+		return entry
+	}
+
+	entry.Err = &RetryError{Attempts: attempts}
+
+	return entry
+}
+
+// sleep waits for d on the configured clock and reports whether it did so before ctx was done.
+func (cfg config) sleep(ctx context.Context, d time.Duration) bool {
+	if ctx == nil {
+		<-cfg.clockOrDefault().After(d)
+		return true
+	}
+
+	select {
+	case <-cfg.clockOrDefault().After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
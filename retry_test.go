@@ -0,0 +1,177 @@
+package error_group
+
+import (
+	"context"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose timers fire immediately. It advances its own time by every delay it
+// is asked to wait for and records those delays so that tests can inspect them.
+type fakeClock struct {
+	delays []time.Duration
+	mutex  sync.Mutex
+	now    time.Time
+}
+
+func (fc *fakeClock) After(d time.Duration) <-chan time.Time {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	fc.now = fc.now.Add(d)
+	fc.delays = append(fc.delays, d)
+
+	fired := make(chan time.Time, 1)
+	fired <- fc.now
+
+	return fired
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	return fc.now
+}
+
+func TestRetry_ErrorGroup(t *testing.T) {
+	clock := &fakeClock{}
+	eg := NewErrorGroup(UseClock(clock), Retry(RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    300 * time.Millisecond,
+	}))
+
+	calls := 0
+	eg.Go(func() error {
+		calls++
+		return errors.New("attempt failed")
+	})
+
+	err := eg.Wait()
+
+	t.Run("verify the task is attempted MaxAttempts times", func(t *testing.T) {
+		assert.Equal(t, 4, calls)
+	})
+	t.Run("verify the delays grow exponentially up to MaxDelay", func(t *testing.T) {
+		assert.DeepEqual(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, clock.delays)
+	})
+	t.Run("verify a single RetryError carrying every attempt is recorded", func(t *testing.T) {
+		assert.Equal(t, 1, eg.Len())
+
+		retryErrors := AsAll[*RetryError](eg)
+		assert.Equal(t, 1, len(retryErrors))
+		assert.Equal(t, 4, len(retryErrors[0].Attempts))
+		assert.Equal(t, "4 attempts failed: attempt 1: attempt failed; attempt 2: attempt failed; attempt 3: attempt failed; attempt 4: attempt failed", err.Error())
+	})
+}
+
+func TestRetry_EventualSuccess(t *testing.T) {
+	eg := NewErrorGroup(UseClock(&fakeClock{}), Retry(RetryPolicy{MaxAttempts: 3}))
+
+	calls := 0
+	eg.Go(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("attempt failed")
+		}
+
+		return nil
+	})
+
+	t.Run("verify a task that eventually succeeds records no error", func(t *testing.T) {
+		assert.Nil(t, eg.Wait())
+		assert.Equal(t, 3, calls)
+	})
+}
+
+func TestRetry_ErrorStatusGroup(t *testing.T) {
+	clock := &fakeClock{}
+	errUnavailable := errors.New("unavailable")
+	esg := NewErrorStatusGroup(UseClock(clock), Retry(RetryPolicy{
+		MaxAttempts:   5,
+		BaseDelay:     time.Second,
+		Multiplier:    3,
+		RetryStatuses: []int{503},
+	}))
+
+	calls := 0
+	esg.Go(func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 503, errUnavailable
+		}
+
+		return 400, errors.New("bad request")
+	})
+
+	status, err := esg.Wait()
+
+	t.Run("verify only the configured statuses are retried", func(t *testing.T) {
+		assert.Equal(t, 3, calls)
+		assert.DeepEqual(t, []time.Duration{time.Second, 3 * time.Second}, clock.delays)
+	})
+	t.Run("verify the status of the last attempt is recorded", func(t *testing.T) {
+		assert.Equal(t, 400, status)
+		assert.Equal(t, 1, esg.LenStatuses())
+	})
+	t.Run("verify the error shows the attempt history", func(t *testing.T) {
+		assert.Equal(t, "lowest status: [200]\nhighest status: [400]\nstatus counts: [400: 1]\nstatus classes: [4xx: 1]\n3 attempts failed: attempt 1: [503] unavailable; attempt 2: [503] unavailable; attempt 3: [400] bad request", err.Error())
+		assert.NotNil(t, esg.Find(errUnavailable))
+	})
+}
+
+func TestRetry_RetryErrors(t *testing.T) {
+	errTransient := errors.New("transient")
+	eg := NewErrorGroup(UseClock(&fakeClock{}), Retry(RetryPolicy{
+		MaxAttempts: 3,
+		RetryErrors: []error{errTransient},
+	}))
+
+	calls := 0
+	permanent := errors.New("permanent")
+	eg.Go(func() error {
+		calls++
+		return permanent
+	})
+
+	t.Run("verify errors that do not match RetryErrors are not retried", func(t *testing.T) {
+		assert.NotNil(t, eg.Wait())
+		assert.Equal(t, permanent, eg.First())
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestRetry_Jitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, Jitter: 0.5}
+
+	t.Run("verify jitter removes at most the configured fraction of the delay", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			delay := policy.delay(1)
+			assert.True(t, delay > 500*time.Millisecond && delay <= time.Second)
+		}
+	})
+}
+
+func TestRetry_StopsWhenTripped(t *testing.T) {
+	eg, _ := NewErrorGroupWithContext(context.Background(), MaxErrors(0), Retry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Hour,
+	}))
+
+	eg.Add(errors.New("trip the group"))
+
+	calls := 0
+	entry := eg.config.runAttempts(eg.ctx, func() Entry {
+		calls++
+		return Entry{Err: errors.New("attempt failed")}
+	})
+
+	t.Run("verify retries stop once the group's context is cancelled", func(t *testing.T) {
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "attempt failed", entry.Err.Error())
+	})
+}
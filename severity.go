@@ -0,0 +1,149 @@
+package error_group
+
+import "reflect"
+
+// Severity classifies the values saved to a group. The zero value is SeverityError so values added
+// with Add, AddError or AddStatusAndError are regular errors.
+type Severity int
+
+const (
+	// SeverityWarning marks a problem that must not fail the operation, e.g. the failure of an
+	// optional branch. Warnings are kept apart from errors and are not part of Error or ToError.
+	SeverityWarning Severity = iota - 1
+	// SeverityError marks a regular error.
+	SeverityError
+	// SeverityFatal marks an error that fails the whole operation. Adding one trips the group.
+	SeverityFatal
+)
+
+// FatalError is returned by ToError, ToStatusAndError and Wait once a group was tripped by
+// AddFatal. Err is the fatal error and Others is a snapshot of the other errors collected by the
+// group, or nil if the group holds no other error.
+type FatalError struct {
+	Err    error
+	Others error
+}
+
+// Error fulfills the builtin.Error interface.
+func (fe *FatalError) Error() string {
+	if fe.Others == nil {
+		return "fatal error: " + fe.Err.Error()
+	}
+
+	return "fatal error: " + fe.Err.Error() + "\n" + fe.Others.Error()
+}
+
+// Unwrap returns the fatal error followed by the other errors collected by the group.
+func (fe *FatalError) Unwrap() []error {
+	if fe.Others == nil {
+		return []error{fe.Err}
+	}
+
+	return []error{fe.Err, fe.Others}
+}
+
+// String fulfills the fmt.Stringer interface.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityFatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// AddFatal adds an error to this error group instance and trips it: ToError returns a *FatalError
+// wrapping err, Go stops starting tasks and the context returned by NewErrorGroupWithContext is
+// cancelled. An error added after the group has tripped is saved as a regular error.
+func (eg *ErrorGroup) AddFatal(err error) {
+	if err == nil {
+		return
+	}
+
+	eg.record(SeverityFatal, 0, []error{err}, nil)
+}
+
+// AddWarning adds a warning to this error group instance. Warnings are reported by Warnings and
+// delivered to subscribers but they are not counted by Len, are not part of Error or ToError and
+// never trip the group.
+func (eg *ErrorGroup) AddWarning(err error) {
+	if err == nil {
+		return
+	}
+
+	eg.record(SeverityWarning, 0, []error{err}, nil)
+}
+
+// Warnings returns a new slice containing every warning saved to this error group instance.
+func (eg *ErrorGroup) Warnings() []error {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	duplicate := make([]error, len(eg.warnings))
+
+	copy(duplicate, eg.warnings)
+
+	return duplicate
+}
+
+// AddFatal adds an error to this error status group instance and trips it: ToStatusAndError returns
+// a *FatalError wrapping err, Go stops starting tasks and the context returned by
+// NewErrorStatusGroupWithContext is cancelled. An error added after the group has tripped is saved
+// as a regular error.
+func (esg *ErrorStatusGroup) AddFatal(err error) {
+	if err == nil {
+		return
+	}
+
+	esg.record(0, []Entry{{Err: err, Severity: SeverityFatal}}, nil)
+}
+
+// AddWarning adds a warning to this error status group instance. Warnings are reported by Warnings
+// and delivered to subscribers but they are not counted by LenErrors, are not part of Error,
+// ToError or ToStatusAndError and never trip the group.
+func (esg *ErrorStatusGroup) AddWarning(err error) {
+	if err == nil {
+		return
+	}
+
+	esg.record(0, []Entry{{Err: err, Severity: SeverityWarning}}, nil)
+}
+
+// Warnings returns a new slice containing every warning saved to this error status group instance.
+func (esg *ErrorStatusGroup) Warnings() []error {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	_, warnings := splitEntries(esg.warnings, 0, len(esg.warnings))
+
+	return warnings
+}
+
+// fatalReason returns the reason a group trips when err is added with SeverityFatal.
+func fatalReason(err error) string {
+	return "fatal error: " + err.Error()
+}
+
+// tripCause returns the cause the context of a group is cancelled with when it trips for reason,
+// fatal being the error added with AddFatal if that is what tripped it.
+func tripCause(fatal error, reason string) error {
+	if fatal != nil {
+		return &FatalError{Err: fatal}
+	}
+
+	return &ThresholdExceededError{Reason: reason}
+}
+
+// sameError reports whether a and b are the same error value. Unlike ==, it does not panic when
+// their dynamic type is not comparable.
+func sameError(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() && a == b
+}
@@ -0,0 +1,137 @@
+package error_group
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"testing"
+)
+
+func TestErrorGroup_AddWarning(t *testing.T) {
+	eg := NewErrorGroup(MaxErrors(0))
+	subscription := eg.Subscribe()
+
+	eg.AddWarning(errors.New("recommendations unavailable"))
+
+	t.Run("verify warnings are not part of the errors", func(t *testing.T) {
+		assert.Equal(t, 0, eg.Len())
+		assert.Nil(t, eg.ToError())
+		assert.Equal(t, "", eg.Error())
+	})
+	t.Run("verify warnings never trip the group", func(t *testing.T) {
+		assert.False(t, eg.Tripped())
+	})
+	t.Run("verify Warnings() returns the warnings", func(t *testing.T) {
+		warnings := eg.Warnings()
+		assert.Equal(t, 1, len(warnings))
+		assert.Equal(t, "recommendations unavailable", warnings[0].Error())
+	})
+	t.Run("verify subscribers receive warnings with their severity", func(t *testing.T) {
+		entry := <-subscription
+		assert.Equal(t, SeverityWarning, entry.Severity)
+	})
+	t.Run("verify the snapshot serializes the warnings", func(t *testing.T) {
+		serialized, err := json.Marshal(eg.Snapshot())
+		assert.Nil(t, err)
+		assert.Equal(t, `{"errors":[],"error_count":0,"warnings":["recommendations unavailable"]}`, string(serialized))
+	})
+	t.Run("verify Reset() removes the warnings", func(t *testing.T) {
+		eg.Reset()
+		assert.Equal(t, 0, len(eg.Warnings()))
+	})
+}
+
+func TestErrorGroup_AddFatal(t *testing.T) {
+	eg, ctx := NewErrorGroupWithContext(context.Background())
+
+	eg.AddWarning(errors.New("warning message"))
+	eg.AddFatal(errors.New("database unreachable"))
+
+	t.Run("verify a fatal error is counted as an error", func(t *testing.T) {
+		assert.Equal(t, 1, eg.Len())
+	})
+	t.Run("verify a fatal error trips the group and cancels its context", func(t *testing.T) {
+		assert.True(t, eg.Tripped())
+		assert.Equal(t, context.Canceled, ctx.Err())
+		assert.False(t, eg.Go(func() error { return nil }))
+	})
+	t.Run("verify the context is cancelled with a FatalError cause", func(t *testing.T) {
+		var fatal *FatalError
+		assert.True(t, errors.As(context.Cause(ctx), &fatal))
+		assert.Equal(t, "database unreachable", fatal.Err.Error())
+	})
+	t.Run("verify ToError() reports the fatal error once and not the warning", func(t *testing.T) {
+		assert.Equal(t, "fatal error: database unreachable", eg.ToError().Error())
+	})
+	t.Run("verify ToError() returns a FatalError and not a ThresholdExceededError", func(t *testing.T) {
+		var fatal *FatalError
+		var thresholdExceeded *ThresholdExceededError
+		assert.True(t, errors.As(eg.ToError(), &fatal))
+		assert.False(t, errors.As(eg.ToError(), &thresholdExceeded))
+		assert.Nil(t, fatal.Others)
+	})
+}
+
+func TestErrorStatusGroup_AddWarning(t *testing.T) {
+	esg := NewErrorStatusGroup()
+
+	esg.AddStatus(200)
+	esg.AddWarning(errors.New("recommendations unavailable"))
+
+	status, err := esg.ToStatusAndError()
+
+	t.Run("verify warnings do not fail the request", func(t *testing.T) {
+		assert.Equal(t, 200, status)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, esg.LenErrors())
+	})
+	t.Run("verify Warnings() returns the warnings", func(t *testing.T) {
+		warnings := esg.Warnings()
+		assert.Equal(t, 1, len(warnings))
+		assert.Equal(t, "recommendations unavailable", warnings[0].Error())
+		assert.Equal(t, 1, len(esg.Snapshot().Warnings()))
+	})
+	t.Run("verify Merge() carries the warnings over", func(t *testing.T) {
+		merged := NewErrorStatusGroup()
+		merged.Merge(esg)
+		assert.Equal(t, 1, len(merged.Warnings()))
+		assert.Equal(t, 1, merged.LenStatuses())
+	})
+}
+
+func TestErrorStatusGroup_AddFatal(t *testing.T) {
+	esg := NewErrorStatusGroup()
+
+	esg.AddStatusAndError(404, errors.New("not found"))
+	esg.AddFatal(errors.New("corrupt index"))
+
+	t.Run("verify a fatal error trips the group", func(t *testing.T) {
+		assert.True(t, esg.Tripped())
+		assert.Equal(t, 2, esg.LenErrors())
+
+		var fatal *FatalError
+		_, err := esg.ToStatusAndError()
+		assert.True(t, errors.As(err, &fatal))
+		assert.Equal(t, "corrupt index", fatal.Err.Error())
+	})
+	t.Run("verify ToStatusAndError() lists the other errors without repeating the fatal one", func(t *testing.T) {
+		status, err := esg.ToStatusAndError()
+		assert.Equal(t, 404, status)
+		assert.Equal(t, "fatal error: corrupt index\n"+
+			"lowest status: [200]\n"+
+			"highest status: [404]\n"+
+			"status counts: [404: 1]\n"+
+			"status classes: [4xx: 1]\n"+
+			"not found", err.Error())
+	})
+}
+
+func TestSeverity_String(t *testing.T) {
+	t.Run("verify String() names every severity", func(t *testing.T) {
+		assert.Equal(t, "warning", SeverityWarning.String())
+		assert.Equal(t, "error", SeverityError.String())
+		assert.Equal(t, "fatal", SeverityFatal.String())
+		assert.Equal(t, "unknown", Severity(7).String())
+	})
+}
@@ -0,0 +1,519 @@
+package error_group
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Snapshot is an immutable, point-in-time copy of an error group instance. Every value
+// it reports was captured under a single lock so its accessors are mutually consistent
+// even while other goroutines continue to add errors to the original group.
+type Snapshot struct {
+	errors     []error
+	fatal      error
+	groups     []namedSnapshot
+	tripReason string
+	warnings   []error
+}
+
+// namedSnapshot is the snapshot of a child error group.
+type namedSnapshot struct {
+	name     string
+	snapshot Snapshot
+}
+
+// snapshotJSON is the serialized form of a Snapshot.
+type snapshotJSON struct {
+	Name              string         `json:"name,omitempty"`
+	Errors            []string       `json:"errors"`
+	ErrorCount        int            `json:"error_count"`
+	ThresholdExceeded string         `json:"threshold_exceeded,omitempty"`
+	Warnings          []string       `json:"warnings,omitempty"`
+	Groups            []snapshotJSON `json:"groups,omitempty"`
+}
+
+// Snapshot captures the current state of this error group instance, in the ordering the group was
+// created with. Child groups are captured one after the other, each under its own lock, once the
+// state of this group has been captured.
+func (eg *ErrorGroup) Snapshot() Snapshot {
+	eg.mutex.Lock()
+	// errors, groups, seqs and warnings are append-only so capped sub-slices can be shared safely.
+	snapshot := Snapshot{
+		errors:     eg.errors[:len(eg.errors):len(eg.errors)],
+		fatal:      eg.fatal,
+		tripReason: eg.tripReason,
+		warnings:   eg.warnings[:len(eg.warnings):len(eg.warnings)],
+	}
+	groups := eg.groups[:len(eg.groups):len(eg.groups)]
+	seqs := eg.seqs[:len(eg.seqs):len(eg.seqs)]
+	eg.mutex.Unlock()
+
+	snapshot.errors = eg.config.order.sortErrors(snapshot.errors, seqs)
+
+	for _, child := range groups {
+		snapshot.groups = append(snapshot.groups, namedSnapshot{name: child.name, snapshot: child.group.Snapshot()})
+	}
+
+	return snapshot
+}
+
+// All returns a new slice containing every error captured by this snapshot.
+func (s Snapshot) All() []error {
+	duplicate := make([]error, len(s.errors))
+
+	copy(duplicate, s.errors)
+
+	return duplicate
+}
+
+// Error fulfills the builtin.Error interface and returns the same string the error group
+// instance would have returned from Error at the time the snapshot was taken.
+func (s Snapshot) Error() string {
+	sb := strings.Builder{}
+
+	s.writeErrors(&sb, 0, "")
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Len returns the number of errors captured by this snapshot, not counting the errors of
+// child groups.
+func (s Snapshot) Len() int {
+	return len(s.errors)
+}
+
+// MarshalJSON fulfills the json.Marshaler interface.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.toJSON(""))
+}
+
+// String fulfills the fmt.Stringer interface and is equivalent to Error.
+func (s Snapshot) String() string {
+	return s.Error()
+}
+
+// ToError converts this snapshot into a single error value. It returns nil when the snapshot
+// holds no errors, a *FatalError if the group had been tripped by AddFatal and a
+// *ThresholdExceededError if it had tripped one of its thresholds.
+func (s Snapshot) ToError() error {
+	if s.fatal != nil {
+		return s.fatalError()
+	}
+
+	if s.tripReason != "" {
+		return s.thresholdExceeded()
+	}
+
+	errMessage := s.Error()
+	if errMessage == "" {
+		return nil
+	}
+
+	return errors.New(errMessage)
+}
+
+// Tripped reports whether the group had crossed one of its thresholds when the snapshot was taken.
+func (s Snapshot) Tripped() bool {
+	return s.tripReason != ""
+}
+
+// Unwrap returns the errors captured by this snapshot followed by the snapshot of each child
+// group that holds errors. Warnings are not included.
+func (s Snapshot) Unwrap() []error {
+	unwrapped := make([]error, 0, len(s.errors)+len(s.groups))
+	unwrapped = append(unwrapped, s.errors...)
+
+	for _, child := range s.groups {
+		if child.snapshot.hasErrors() {
+			unwrapped = append(unwrapped, child.snapshot)
+		}
+	}
+
+	return unwrapped
+}
+
+// Warnings returns a new slice containing every warning captured by this snapshot, not counting the
+// warnings of child groups.
+func (s Snapshot) Warnings() []error {
+	duplicate := make([]error, len(s.warnings))
+
+	copy(duplicate, s.warnings)
+
+	return duplicate
+}
+
+// fatalError returns the error reporting that the group was tripped by AddFatal. The fatal error
+// is left out of the other errors so that its message is not repeated.
+func (s Snapshot) fatalError() error {
+	others := s
+	others.errors = make([]error, 0, len(s.errors))
+
+	found := false
+	for _, currentError := range s.errors {
+		if !found && sameError(currentError, s.fatal) {
+			found = true
+			continue
+		}
+
+		others.errors = append(others.errors, currentError)
+	}
+
+	if !others.hasErrors() {
+		return &FatalError{Err: s.fatal}
+	}
+
+	return &FatalError{Err: s.fatal, Others: others}
+}
+
+// thresholdExceeded returns the error reporting that the group tripped one of its thresholds.
+func (s Snapshot) thresholdExceeded() error {
+	if !s.hasErrors() {
+		return &ThresholdExceededError{Reason: s.tripReason}
+	}
+
+	return &ThresholdExceededError{Err: s, Reason: s.tripReason}
+}
+
+// hasErrors reports whether this snapshot or any of its child snapshots holds an error.
+func (s Snapshot) hasErrors() bool {
+	if len(s.errors) > 0 {
+		return true
+	}
+
+	for _, child := range s.groups {
+		if child.snapshot.hasErrors() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toJSON converts this snapshot and its child snapshots into their serialized form.
+func (s Snapshot) toJSON(name string) snapshotJSON {
+	serialized := snapshotJSON{
+		Name:              name,
+		Errors:            errorStrings(s.errors),
+		ErrorCount:        len(s.errors),
+		ThresholdExceeded: s.tripReason,
+	}
+
+	if len(s.warnings) > 0 {
+		serialized.Warnings = errorStrings(s.warnings)
+	}
+
+	for _, child := range s.groups {
+		serialized.Groups = append(serialized.Groups, child.snapshot.toJSON(child.name))
+	}
+
+	return serialized
+}
+
+// writeErrors writes the errors of this snapshot followed by those of its child snapshots.
+func (s Snapshot) writeErrors(sb *strings.Builder, depth int, path string) {
+	for _, currentError := range s.errors {
+		writeError(sb, depth, path, currentError)
+	}
+
+	for _, child := range s.groups {
+		child.snapshot.writeErrors(sb, depth+1, joinPath(path, child.name))
+	}
+}
+
+// StatusSnapshot is an immutable, point-in-time copy of an error status group instance.
+// Every value it reports was captured under a single lock so its accessors are mutually
+// consistent even while other goroutines continue to add errors and statuses.
+type StatusSnapshot struct {
+	entries       []Entry
+	errorCount    int
+	fatal         error
+	groups        []namedStatusSnapshot
+	highestStatus int
+	lowestStatus  int
+	optional      []Entry
+	statusCount   int
+	tripReason    string
+	warnings      []Entry
+}
+
+// namedStatusSnapshot is the snapshot of a child error status group.
+type namedStatusSnapshot struct {
+	name     string
+	snapshot StatusSnapshot
+}
+
+// statusSnapshotJSON is the serialized form of a StatusSnapshot.
+type statusSnapshotJSON struct {
+	Name              string               `json:"name,omitempty"`
+	Errors            []string             `json:"errors"`
+	ErrorCount        int                  `json:"error_count"`
+	HighestStatus     int                  `json:"highest_status"`
+	LowestStatus      int                  `json:"lowest_status"`
+	StatusCount       int                  `json:"status_count"`
+	Statuses          []int                `json:"statuses"`
+	StatusHistogram   StatusHistogram      `json:"status_histogram"`
+	ThresholdExceeded string               `json:"threshold_exceeded,omitempty"`
+	Warnings          []string             `json:"warnings,omitempty"`
+	Groups            []statusSnapshotJSON `json:"groups,omitempty"`
+}
+
+// Snapshot captures the current state of this error status group instance, in the ordering the
+// group was created with. Child groups are captured one after the other, each under its own lock,
+// once the state of this group has been captured. The lowest and highest status values of the
+// snapshot include those of its children.
+func (esg *ErrorStatusGroup) Snapshot() StatusSnapshot {
+	esg.mutex.Lock()
+	lowestStatus, highestStatus := esg.statusRangeLocked()
+	// entries, groups, optional, seqs and warnings are append-only so capped sub-slices can be shared
+	// safely.
+	snapshot := StatusSnapshot{
+		entries:       esg.entries[:len(esg.entries):len(esg.entries)],
+		errorCount:    esg.errorCount,
+		fatal:         esg.fatal,
+		highestStatus: highestStatus,
+		lowestStatus:  lowestStatus,
+		optional:      esg.optional[:len(esg.optional):len(esg.optional)],
+		statusCount:   esg.statusCount,
+		tripReason:    esg.tripReason,
+		warnings:      esg.warnings[:len(esg.warnings):len(esg.warnings)],
+	}
+	groups := esg.groups[:len(esg.groups):len(esg.groups)]
+	seqs := esg.seqs[:len(esg.seqs):len(esg.seqs)]
+	esg.mutex.Unlock()
+
+	snapshot.entries = esg.config.order.sortEntries(snapshot.entries, seqs)
+
+	for _, child := range groups {
+		childSnapshot := child.group.Snapshot()
+
+		if childSnapshot.highestStatus > snapshot.highestStatus {
+			snapshot.highestStatus = childSnapshot.highestStatus
+		}
+
+		if childSnapshot.lowestStatus < snapshot.lowestStatus {
+			snapshot.lowestStatus = childSnapshot.lowestStatus
+		}
+
+		snapshot.groups = append(snapshot.groups, namedStatusSnapshot{name: child.name, snapshot: childSnapshot})
+	}
+
+	return snapshot
+}
+
+// All returns two new slices - one containing every status value and the other containing
+// every error value captured by this snapshot.
+func (s StatusSnapshot) All() ([]int, []error) {
+	return splitEntries(s.entries, s.statusCount, s.errorCount)
+}
+
+// Error fulfills the builtin.Error interface and returns the same string the error status
+// group instance would have returned from Error at the time the snapshot was taken.
+func (s StatusSnapshot) Error() string {
+	if !s.hasErrors() {
+		return ""
+	}
+
+	sb := strings.Builder{}
+
+	sb.WriteString("lowest status: [")
+	sb.WriteString(strconv.Itoa(s.lowestStatus))
+	sb.WriteString("]\n")
+	sb.WriteString("highest status: [")
+	sb.WriteString(strconv.Itoa(s.highestStatus))
+	sb.WriteString("]\n")
+	s.writeStatusHeader(&sb)
+
+	s.writeErrors(&sb, 0, "")
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// HighestStatus returns the highest status value captured by this snapshot or any of its
+// child snapshots.
+func (s StatusSnapshot) HighestStatus() int {
+	return s.highestStatus
+}
+
+// LenErrors returns the number of error values captured by this snapshot, not counting the
+// errors of child groups.
+func (s StatusSnapshot) LenErrors() int {
+	return s.errorCount
+}
+
+// LenStatuses returns the number of status values captured by this snapshot, not counting the
+// statuses of child groups.
+func (s StatusSnapshot) LenStatuses() int {
+	return s.statusCount
+}
+
+// LowestStatus returns the lowest status value captured by this snapshot or any of its
+// child snapshots.
+func (s StatusSnapshot) LowestStatus() int {
+	return s.lowestStatus
+}
+
+// MarshalJSON fulfills the json.Marshaler interface.
+func (s StatusSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.toJSON(""))
+}
+
+// String fulfills the fmt.Stringer interface and is equivalent to Error.
+func (s StatusSnapshot) String() string {
+	return s.Error()
+}
+
+// ToError converts this snapshot into a single error value. It returns nil when the snapshot
+// holds no errors, a *FatalError if the group had been tripped by AddFatal and a
+// *ThresholdExceededError if it had tripped one of its thresholds.
+func (s StatusSnapshot) ToError() error {
+	if s.fatal != nil {
+		return s.fatalError()
+	}
+
+	if s.tripReason != "" {
+		return s.thresholdExceeded()
+	}
+
+	errMessage := s.Error()
+	if errMessage == "" {
+		return nil
+	}
+
+	return errors.New(errMessage)
+}
+
+// ToStatusAndError returns the highest status value captured by this snapshot in
+// conjunction with the error value returned by ToError.
+func (s StatusSnapshot) ToStatusAndError() (int, error) {
+	return s.highestStatus, s.ToError()
+}
+
+// Tripped reports whether the group had crossed one of its thresholds when the snapshot was taken.
+func (s StatusSnapshot) Tripped() bool {
+	return s.tripReason != ""
+}
+
+// Unwrap returns the errors captured by this snapshot followed by the snapshot of each child
+// group that holds errors. Warnings are not included.
+func (s StatusSnapshot) Unwrap() []error {
+	_, unwrapped := splitEntries(s.entries, 0, s.errorCount+len(s.groups))
+
+	for _, child := range s.groups {
+		if child.snapshot.hasErrors() {
+			unwrapped = append(unwrapped, child.snapshot)
+		}
+	}
+
+	return unwrapped
+}
+
+// Warnings returns a new slice containing every warning captured by this snapshot, not counting the
+// warnings of child groups.
+func (s StatusSnapshot) Warnings() []error {
+	_, warnings := splitEntries(s.warnings, 0, len(s.warnings))
+
+	return warnings
+}
+
+// fatalError returns the error reporting that the group was tripped by AddFatal. The fatal error
+// is left out of the other errors so that its message is not repeated, while its status, if any,
+// is kept.
+func (s StatusSnapshot) fatalError() error {
+	others := s
+	others.entries = make([]Entry, len(s.entries))
+
+	copy(others.entries, s.entries)
+
+	for i, entry := range others.entries {
+		if entry.Severity == SeverityFatal && sameError(entry.Err, s.fatal) {
+			others.entries[i].Err = nil
+			others.errorCount--
+
+			break
+		}
+	}
+
+	if !others.hasErrors() {
+		return &FatalError{Err: s.fatal}
+	}
+
+	return &FatalError{Err: s.fatal, Others: others}
+}
+
+// thresholdExceeded returns the error reporting that the group tripped one of its thresholds.
+func (s StatusSnapshot) thresholdExceeded() error {
+	if !s.hasErrors() {
+		return &ThresholdExceededError{Reason: s.tripReason}
+	}
+
+	return &ThresholdExceededError{Err: s, Reason: s.tripReason}
+}
+
+// hasErrors reports whether this snapshot or any of its child snapshots holds an error.
+func (s StatusSnapshot) hasErrors() bool {
+	if s.errorCount > 0 {
+		return true
+	}
+
+	for _, child := range s.groups {
+		if child.snapshot.hasErrors() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toJSON converts this snapshot and its child snapshots into their serialized form.
+func (s StatusSnapshot) toJSON(name string) statusSnapshotJSON {
+	statuses, errs := splitEntries(s.entries, s.statusCount, s.errorCount)
+
+	serialized := statusSnapshotJSON{
+		Name:              name,
+		Errors:            errorStrings(errs),
+		ErrorCount:        s.errorCount,
+		HighestStatus:     s.highestStatus,
+		LowestStatus:      s.lowestStatus,
+		StatusCount:       s.statusCount,
+		Statuses:          statuses,
+		StatusHistogram:   s.StatusHistogram(),
+		ThresholdExceeded: s.tripReason,
+	}
+
+	if len(s.warnings) > 0 {
+		serialized.Warnings = errorStrings(s.Warnings())
+	}
+
+	for _, child := range s.groups {
+		serialized.Groups = append(serialized.Groups, child.snapshot.toJSON(child.name))
+	}
+
+	return serialized
+}
+
+// writeErrors writes the errors of this snapshot followed by those of its child snapshots.
+func (s StatusSnapshot) writeErrors(sb *strings.Builder, depth int, path string) {
+	for _, entry := range s.entries {
+		if entry.Err != nil {
+			writeError(sb, depth, path, entry.Err)
+		}
+	}
+
+	for _, child := range s.groups {
+		child.snapshot.writeErrors(sb, depth+1, joinPath(path, child.name))
+	}
+}
+
+// errorStrings returns the message of every error in errs. It never returns nil so the
+// JSON serialization always contains an array.
+func errorStrings(errs []error) []string {
+	messages := make([]string, len(errs))
+
+	for i, currentError := range errs {
+		messages[i] = currentError.Error()
+	}
+
+	return messages
+}
@@ -0,0 +1,99 @@
+package error_group
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jgroeneveld/trial/assert"
+	"sync"
+	"testing"
+)
+
+func TestErrorGroup_Snapshot(t *testing.T) {
+	eg := NewErrorGroup()
+	eg.Add(errors.New("first message"))
+	eg.Add(errors.New("last message"))
+
+	snapshot := eg.Snapshot()
+	eg.Add(errors.New("added after snapshot"))
+
+	t.Run("verify Snapshot() is not affected by later calls to Add()", func(t *testing.T) {
+		assert.Equal(t, 2, snapshot.Len())
+		assert.Equal(t, 2, len(snapshot.All()))
+		assert.Equal(t, 3, eg.Len())
+	})
+	t.Run("verify Snapshot() Error() matches the group at the time it was taken", func(t *testing.T) {
+		assert.Equal(t, "first message\nlast message", snapshot.Error())
+		assert.Equal(t, snapshot.Error(), fmt.Sprint(snapshot))
+	})
+	t.Run("verify Snapshot() serializes to JSON", func(t *testing.T) {
+		data, err := json.Marshal(snapshot)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"errors":["first message","last message"],"error_count":2}`, string(data))
+	})
+	t.Run("verify Snapshot() of an empty group has no error", func(t *testing.T) {
+		empty := NewErrorGroup().Snapshot()
+		assert.Nil(t, empty.ToError())
+
+		data, err := json.Marshal(empty)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"errors":[],"error_count":0}`, string(data))
+	})
+}
+
+func TestErrorStatusGroup_Snapshot(t *testing.T) {
+	esg := NewErrorStatusGroup()
+	esg.AddStatusAndError(100, errors.New("first message"))
+	esg.AddStatusAndError(300, errors.New("last message"))
+
+	snapshot := esg.Snapshot()
+	esg.AddStatusAndError(500, errors.New("added after snapshot"))
+
+	t.Run("verify Snapshot() is not affected by later calls to AddStatusAndError()", func(t *testing.T) {
+		statuses, errs := snapshot.All()
+		assert.Equal(t, 2, len(statuses))
+		assert.Equal(t, 2, len(errs))
+		assert.Equal(t, 2, snapshot.LenErrors())
+		assert.Equal(t, 2, snapshot.LenStatuses())
+		assert.Equal(t, 300, snapshot.HighestStatus())
+		assert.Equal(t, 100, snapshot.LowestStatus())
+	})
+	t.Run("verify Snapshot() Error() matches the group at the time it was taken", func(t *testing.T) {
+		assert.Equal(t, "lowest status: [100]\nhighest status: [300]\nstatus counts: [100: 1, 300: 1]\nstatus classes: [1xx: 1, 3xx: 1]\nfirst message\nlast message", snapshot.Error())
+	})
+	t.Run("verify Snapshot() serializes to JSON", func(t *testing.T) {
+		data, err := json.Marshal(snapshot)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"errors":["first message","last message"],"error_count":2,"highest_status":300,"lowest_status":100,"status_count":2,"statuses":[100,300],"status_histogram":{"classes":{"1xx":1,"3xx":1},"codes":{"100":1,"300":1},"total":2}}`, string(data))
+	})
+	t.Run("verify Snapshot() ToStatusAndError() matches the group at the time it was taken", func(t *testing.T) {
+		status, err := snapshot.ToStatusAndError()
+		assert.Equal(t, 300, status)
+		assert.Equal(t, snapshot.Error(), err.Error())
+	})
+}
+
+func TestErrorStatusGroup_SnapshotConsistency(t *testing.T) {
+	esg := NewErrorStatusGroup()
+
+	var wg sync.WaitGroup
+	numToAdd := 10000
+
+	for i := 0; i < numToAdd; i++ {
+		wg.Add(1)
+		go func() {
+			esg.AddStatusAndError(GenerateRandomNumber(), errors.New(generateRandomString(10)))
+			wg.Done()
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		snapshot := esg.Snapshot()
+		statuses, errs := snapshot.All()
+
+		assert.Equal(t, snapshot.LenErrors(), snapshot.LenStatuses())
+		assert.Equal(t, len(errs), len(statuses))
+	}
+
+	wg.Wait()
+}
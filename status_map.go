@@ -0,0 +1,118 @@
+package error_group
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// StatusMap maps errors to status values. An error implementing StatusCode() int anywhere in its
+// tree always maps to the status it reports. Otherwise the registered rules are consulted, the most
+// recently registered first, so later registrations override earlier ones. A StatusMap is safe for
+// concurrent use.
+type StatusMap struct {
+	mutex sync.RWMutex
+	rules []statusRule
+}
+
+// statusRule maps every error for which match returns true to status.
+type statusRule struct {
+	match  func(error) bool
+	status int
+}
+
+// DefaultStatusMap maps common standard library errors to HTTP status values. It can be extended
+// with Register, in which case the additions are visible to every group using it; use Clone to
+// extend a private copy instead.
+var DefaultStatusMap = newDefaultStatusMap()
+
+// NewStatusMap returns an empty StatusMap.
+func NewStatusMap() *StatusMap {
+	return &StatusMap{}
+}
+
+// InferStatuses makes AddError look up the status of every error it is given in statusMap. Errors
+// statusMap does not know about are added without a status, as they would be without this option.
+// It has no effect on an error group.
+func InferStatuses(statusMap *StatusMap) Option {
+	return func(cfg *config) {
+		cfg.statusMap = statusMap
+	}
+}
+
+// RegisterAs maps every error for which errors.As finds an error of type T to status.
+func RegisterAs[T error](statusMap *StatusMap, status int) {
+	statusMap.RegisterFunc(func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}, status)
+}
+
+// Clone returns a new StatusMap holding the same rules as this one.
+func (sm *StatusMap) Clone() *StatusMap {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	return &StatusMap{rules: append([]statusRule(nil), sm.rules...)}
+}
+
+// Lookup returns the status err maps to and whether a mapping was found.
+func (sm *StatusMap) Lookup(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	var coder interface{ StatusCode() int }
+	if errors.As(err, &coder) {
+		return coder.StatusCode(), true
+	}
+
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	for i := len(sm.rules) - 1; i >= 0; i-- {
+		if sm.rules[i].match(err) {
+			return sm.rules[i].status, true
+		}
+	}
+
+	return 0, false
+}
+
+// Register maps every error for which errors.Is reports a match with target to status.
+func (sm *StatusMap) Register(target error, status int) {
+	sm.RegisterFunc(func(err error) bool {
+		return errors.Is(err, target)
+	}, status)
+}
+
+// RegisterFunc maps every error for which match returns true to status.
+func (sm *StatusMap) RegisterFunc(match func(error) bool, status int) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.rules = append(sm.rules, statusRule{match: match, status: status})
+}
+
+// newDefaultStatusMap returns the StatusMap assigned to DefaultStatusMap.
+func newDefaultStatusMap() *StatusMap {
+	statusMap := NewStatusMap()
+
+	statusMap.Register(errors.ErrUnsupported, http.StatusNotImplemented)
+	statusMap.Register(strconv.ErrRange, http.StatusBadRequest)
+	statusMap.Register(strconv.ErrSyntax, http.StatusBadRequest)
+	statusMap.Register(sql.ErrNoRows, http.StatusNotFound)
+	statusMap.Register(fs.ErrNotExist, http.StatusNotFound)
+	statusMap.Register(fs.ErrExist, http.StatusConflict)
+	statusMap.Register(fs.ErrPermission, http.StatusForbidden)
+	statusMap.Register(os.ErrDeadlineExceeded, http.StatusGatewayTimeout)
+	statusMap.Register(context.Canceled, 499)
+	statusMap.Register(context.DeadlineExceeded, http.StatusGatewayTimeout)
+
+	return statusMap
+}
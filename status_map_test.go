@@ -0,0 +1,105 @@
+package error_group
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jgroeneveld/trial/assert"
+	"io/fs"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// teapotError is an error that reports its own status.
+type teapotError struct{}
+
+func (teapotError) Error() string {
+	return "short and stout"
+}
+
+func (teapotError) StatusCode() int {
+	return http.StatusTeapot
+}
+
+func TestDefaultStatusMap(t *testing.T) {
+	t.Run("verify common standard library errors are mapped", func(t *testing.T) {
+		for err, expected := range map[error]int{
+			sql.ErrNoRows:            http.StatusNotFound,
+			context.DeadlineExceeded: http.StatusGatewayTimeout,
+			context.Canceled:         499,
+			os.ErrPermission:         http.StatusForbidden,
+			os.ErrNotExist:           http.StatusNotFound,
+			errors.ErrUnsupported:    http.StatusNotImplemented,
+		} {
+			status, ok := DefaultStatusMap.Lookup(fmt.Errorf("wrapped: %w", err))
+			assert.True(t, ok)
+			assert.Equal(t, expected, status)
+		}
+	})
+	t.Run("verify errors.As finds wrapped standard library error types", func(t *testing.T) {
+		_, err := os.Open("/" + generateRandomString(20))
+
+		status, ok := DefaultStatusMap.Lookup(err)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+	t.Run("verify a StatusCode() method takes precedence", func(t *testing.T) {
+		status, ok := DefaultStatusMap.Lookup(fmt.Errorf("%w: %w", teapotError{}, sql.ErrNoRows))
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusTeapot, status)
+	})
+	t.Run("verify unknown errors are not mapped", func(t *testing.T) {
+		_, ok := DefaultStatusMap.Lookup(errors.New(generateRandomString(10)))
+		assert.False(t, ok)
+
+		_, ok = DefaultStatusMap.Lookup(nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestStatusMap_Register(t *testing.T) {
+	errBusy := errors.New("busy")
+	statusMap := DefaultStatusMap.Clone()
+
+	statusMap.Register(errBusy, http.StatusServiceUnavailable)
+	statusMap.Register(sql.ErrNoRows, http.StatusNoContent)
+	RegisterAs[*fs.PathError](statusMap, http.StatusBadRequest)
+
+	t.Run("verify registered errors are mapped", func(t *testing.T) {
+		status, ok := statusMap.Lookup(errBusy)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+	})
+	t.Run("verify later registrations override earlier ones", func(t *testing.T) {
+		status, _ := statusMap.Lookup(sql.ErrNoRows)
+		assert.Equal(t, http.StatusNoContent, status)
+
+		status, _ = statusMap.Lookup(&fs.PathError{Op: "open", Err: fs.ErrNotExist})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+	t.Run("verify Clone() leaves the original untouched", func(t *testing.T) {
+		_, ok := DefaultStatusMap.Lookup(errBusy)
+		assert.False(t, ok)
+	})
+}
+
+func TestInferStatuses(t *testing.T) {
+	esg := NewErrorStatusGroup(InferStatuses(DefaultStatusMap))
+
+	esg.AddError(fmt.Errorf("loading teacher: %w", sql.ErrNoRows))
+	esg.AddError(errors.New("unmapped"))
+
+	t.Run("verify AddError() infers the status of mapped errors", func(t *testing.T) {
+		assert.Equal(t, 2, esg.LenErrors())
+		assert.Equal(t, 1, esg.LenStatuses())
+		assert.Equal(t, http.StatusNotFound, esg.HighestStatus())
+	})
+	t.Run("verify groups without InferStatuses do not infer statuses", func(t *testing.T) {
+		plain := NewErrorStatusGroup()
+		plain.AddError(sql.ErrNoRows)
+
+		assert.Equal(t, 0, plain.LenStatuses())
+	})
+}
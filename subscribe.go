@@ -0,0 +1,95 @@
+package error_group
+
+// subscriptionBuffer is the capacity of the channels returned by Subscribe.
+const subscriptionBuffer = 64
+
+// hooks holds the callbacks registered with OnError and OnStatus.
+type hooks struct {
+	errorHooks  []func(error)
+	statusHooks []func(int)
+}
+
+// snapshot returns a copy of h that is not affected by later registrations.
+func (h hooks) snapshot() hooks {
+	return hooks{
+		errorHooks:  h.errorHooks[:len(h.errorHooks):len(h.errorHooks)],
+		statusHooks: h.statusHooks[:len(h.statusHooks):len(h.statusHooks)],
+	}
+}
+
+// runEntries calls the registered hooks for every error and status carried by entries that are
+// neither warnings nor optional.
+func (h hooks) runEntries(entries []Entry) {
+	for _, entry := range entries {
+		if entry.Severity == SeverityWarning || entry.Optional {
+			continue
+		}
+
+		if entry.HasStatus {
+			for _, hook := range h.statusHooks {
+				hook(entry.Status)
+			}
+		}
+
+		if entry.Err != nil {
+			for _, hook := range h.errorHooks {
+				hook(entry.Err)
+			}
+		}
+	}
+}
+
+// runErrors calls the registered error hooks for every error in errs.
+func (h hooks) runErrors(errs []error) {
+	for _, err := range errs {
+		for _, hook := range h.errorHooks {
+			hook(err)
+		}
+	}
+}
+
+// subscribers fans entries out to the channels returned by Subscribe.
+type subscribers struct {
+	channels []chan Entry
+	dropped  int
+}
+
+// add registers a new subscription channel and returns it.
+func (s *subscribers) add() <-chan Entry {
+	channel := make(chan Entry, subscriptionBuffer)
+
+	s.channels = append(s.channels, channel)
+
+	return channel
+}
+
+// close closes every subscription channel and forgets about them.
+func (s *subscribers) close() {
+	for _, channel := range s.channels {
+		close(channel)
+	}
+
+	s.channels = nil
+}
+
+// publish delivers entry to every subscription channel that has room for it and counts the
+// channels that did not.
+func (s *subscribers) publish(entry Entry) {
+	for _, channel := range s.channels {
+		select {
+		case channel <- entry:
+		default:
+			s.dropped++
+		}
+	}
+}
+
+// closedSubscription returns a channel that is already closed. It is handed out by Subscribe
+// once a group has been closed.
+func closedSubscription() <-chan Entry {
+	channel := make(chan Entry)
+
+	close(channel)
+
+	return channel
+}
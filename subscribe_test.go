@@ -0,0 +1,118 @@
+package error_group
+
+import (
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"sync"
+	"testing"
+)
+
+func TestErrorGroup_OnError(t *testing.T) {
+	eg := NewErrorGroup()
+
+	var mutex sync.Mutex
+	var observed []string
+	eg.OnError(func(err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		observed = append(observed, err.Error())
+	})
+
+	eg.Add(errors.New("first message"))
+	eg.Add(nil)
+	eg.Add(errors.New("last message"))
+
+	t.Run("verify OnError() hooks observe every error in order", func(t *testing.T) {
+		assert.DeepEqual(t, []string{"first message", "last message"}, observed)
+	})
+	t.Run("verify OnError() hooks may call back into the group", func(t *testing.T) {
+		lengths := 0
+		eg.OnError(func(error) {
+			lengths += eg.Len()
+		})
+
+		eg.Add(errors.New("third message"))
+		assert.Equal(t, 3, lengths)
+	})
+}
+
+func TestErrorGroup_Subscribe(t *testing.T) {
+	eg := NewErrorGroup()
+	subscription := eg.Subscribe()
+
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		eg.Add(errors.New(generateRandomString(10)))
+	}
+
+	eg.Close()
+
+	received := 0
+	for range subscription {
+		received++
+	}
+
+	t.Run("verify Subscribe() delivers entries until the buffer is full", func(t *testing.T) {
+		assert.Equal(t, subscriptionBuffer, received)
+	})
+	t.Run("verify Dropped() counts entries that did not fit in the buffer", func(t *testing.T) {
+		assert.Equal(t, 10, eg.Dropped())
+	})
+	t.Run("verify Subscribe() on a closed group returns a closed channel", func(t *testing.T) {
+		_, ok := <-eg.Subscribe()
+		assert.False(t, ok)
+	})
+}
+
+func TestErrorStatusGroup_OnStatus(t *testing.T) {
+	esg := NewErrorStatusGroup()
+
+	var statuses []int
+	var messages []string
+	esg.OnStatus(func(status int) {
+		statuses = append(statuses, status)
+	})
+	esg.OnError(func(err error) {
+		messages = append(messages, err.Error())
+	})
+
+	esg.AddStatusAndError(503, errors.New("first message"))
+	esg.AddStatus(200)
+	esg.AddError(errors.New("last message"))
+
+	t.Run("verify OnStatus() hooks observe every status", func(t *testing.T) {
+		assert.DeepEqual(t, []int{503, 200}, statuses)
+	})
+	t.Run("verify OnError() hooks observe every error", func(t *testing.T) {
+		assert.DeepEqual(t, []string{"first message", "last message"}, messages)
+	})
+	t.Run("verify hooks are not called for values added after Close()", func(t *testing.T) {
+		esg.Close()
+		esg.AddStatus(500)
+		assert.Equal(t, 2, len(statuses))
+	})
+}
+
+func TestErrorStatusGroup_Subscribe(t *testing.T) {
+	esg := NewErrorStatusGroup()
+	subscription := esg.Subscribe()
+
+	esg.AddStatusAndError(503, errors.New("first message"))
+	esg.AddStatus(200)
+
+	t.Run("verify Subscribe() delivers entries in the order they were added", func(t *testing.T) {
+		first := <-subscription
+		assert.Equal(t, 503, first.Status)
+		assert.Equal(t, "first message", first.Err.Error())
+
+		second := <-subscription
+		assert.Equal(t, 200, second.Status)
+		assert.Nil(t, second.Err)
+	})
+	t.Run("verify Close() closes the subscription", func(t *testing.T) {
+		esg.Close()
+		_, ok := <-subscription
+		assert.False(t, ok)
+		assert.Equal(t, 0, esg.Dropped())
+	})
+}
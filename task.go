@@ -0,0 +1,185 @@
+package error_group
+
+import "context"
+
+// NewErrorGroupWithContext returns a new error group instance and a context derived from ctx. The
+// context is cancelled the first time the group crosses one of its thresholds, with a
+// *ThresholdExceededError as its cause, when AddFatal trips it, with a *FatalError as its cause, or
+// when Wait returns, whichever happens first.
+func NewErrorGroupWithContext(ctx context.Context, opts ...Option) (*ErrorGroup, context.Context) {
+	eg := NewErrorGroup(opts...)
+	eg.ctx, eg.cancel = context.WithCancelCause(ctx)
+
+	return eg, eg.ctx
+}
+
+// NewErrorStatusGroupWithContext returns a new error status group instance and a context derived
+// from ctx. The context is cancelled the first time the group crosses one of its thresholds, with
+// a *ThresholdExceededError as its cause, when AddFatal trips it, with a *FatalError as its cause,
+// or when Wait returns, whichever happens first.
+func NewErrorStatusGroupWithContext(ctx context.Context, opts ...Option) (*ErrorStatusGroup, context.Context) {
+	esg := NewErrorStatusGroup(opts...)
+	esg.ctx, esg.cancel = context.WithCancelCause(ctx)
+
+	return esg, esg.ctx
+}
+
+// Go calls f in a new goroutine and adds the error it returns to this error group instance. A nil
+// error is recorded as a successful outcome for MaxErrorRate. f is retried if the group was created
+// with Retry and a panic in f is recovered and added as a *PanicError. Go does not start f and
+// returns false once the group has crossed one of its thresholds or been closed.
+func (eg *ErrorGroup) Go(f func() error) bool {
+	return eg.launch(func() Entry {
+		return Entry{Err: f()}
+	})
+}
+
+// Wait blocks until every function started with Go has returned, cancels the context returned by
+// NewErrorGroupWithContext and returns ToError, finalizing the group.
+func (eg *ErrorGroup) Wait() error {
+	eg.tasks.Wait()
+
+	if eg.cancel != nil {
+		eg.cancel(nil)
+	}
+
+	return eg.ToError()
+}
+
+// addSuccess records a task that returned a nil error.
+func (eg *ErrorGroup) addSuccess() {
+	eg.mutex.Lock()
+
+	if eg.closed {
+		eg.mutex.Unlock()
+		return
+	}
+
+	eg.successes++
+	trip := eg.checkThresholdsLocked()
+	eg.mutex.Unlock()
+
+	if trip != nil {
+		trip()
+	}
+}
+
+// launch runs attempt in a new goroutine, retrying it according to the group's retry policy, and
+// records the resulting entry. A panic is recovered and recorded as a *PanicError.
+func (eg *ErrorGroup) launch(attempt func() Entry) bool {
+	seq, ok := eg.startTask()
+	if !ok {
+		return false
+	}
+
+	go func() {
+		defer eg.tasks.Done()
+
+		start := eg.config.clockOrDefault().Now()
+		entry := callProtected(func() Entry {
+			return eg.config.runAttempts(eg.ctx, attempt)
+		})
+
+		eg.mutex.Lock()
+		eg.running--
+		eg.stats.finishLocked(eg.config.clockOrDefault().Now().Sub(start), entry)
+		eg.mutex.Unlock()
+
+		if entry.Err != nil {
+			eg.record(SeverityError, seq, []error{entry.Err}, nil)
+		} else {
+			eg.addSuccess()
+		}
+	}()
+
+	return true
+}
+
+// startTask registers a new task with this error group instance unless it has tripped or been
+// closed, and returns its submission index.
+func (eg *ErrorGroup) startTask() (uint64, bool) {
+	eg.mutex.Lock()
+	defer eg.mutex.Unlock()
+
+	if eg.closed || eg.tripReason != "" {
+		return 0, false
+	}
+
+	eg.running++
+	eg.tasks.Add(1)
+
+	return eg.nextSeqLocked(0), true
+}
+
+// Go calls f in a new goroutine and adds the status and error it returns to this error status
+// group instance. f is retried if the group was created with Retry, in which case only the status
+// of the last attempt is added. A panic in f is recovered and added as a *PanicError with a status
+// of 500. Go does not start f and returns false once the group has crossed one of its thresholds
+// or been closed.
+func (esg *ErrorStatusGroup) Go(f func() (int, error)) bool {
+	return esg.launch(false, func() Entry {
+		status, err := f()
+
+		return Entry{Err: err, HasStatus: true, Status: status}
+	})
+}
+
+// Wait blocks until every function started with Go has returned, cancels the context returned by
+// NewErrorStatusGroupWithContext and returns ToStatusAndError, finalizing the group.
+func (esg *ErrorStatusGroup) Wait() (int, error) {
+	esg.tasks.Wait()
+
+	if esg.cancel != nil {
+		esg.cancel(nil)
+	}
+
+	return esg.ToStatusAndError()
+}
+
+// launch runs attempt in a new goroutine, retrying it according to the group's retry policy, and
+// records the resulting entry, as an optional one if optional is true. A panic is recovered and
+// recorded as a *PanicError.
+func (esg *ErrorStatusGroup) launch(optional bool, attempt func() Entry) bool {
+	seq, ok := esg.startTask()
+	if !ok {
+		return false
+	}
+
+	go func() {
+		defer esg.tasks.Done()
+
+		start := esg.config.clockOrDefault().Now()
+		entry := callProtected(func() Entry {
+			return esg.config.runAttempts(esg.ctx, attempt)
+		})
+
+		esg.mutex.Lock()
+		esg.running--
+		esg.stats.finishLocked(esg.config.clockOrDefault().Now().Sub(start), entry)
+		esg.mutex.Unlock()
+
+		if optional {
+			entry = optionalEntry(entry)
+		}
+
+		esg.record(seq, []Entry{entry}, nil)
+	}()
+
+	return true
+}
+
+// startTask registers a new task with this error status group instance unless it has tripped or
+// been closed, and returns its submission index.
+func (esg *ErrorStatusGroup) startTask() (uint64, bool) {
+	esg.mutex.Lock()
+	defer esg.mutex.Unlock()
+
+	if esg.closed || esg.tripReason != "" {
+		return 0, false
+	}
+
+	esg.running++
+	esg.tasks.Add(1)
+
+	return esg.nextSeqLocked(0), true
+}
@@ -0,0 +1,60 @@
+package error_group
+
+import (
+	"context"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"testing"
+)
+
+func TestErrorGroup_Go(t *testing.T) {
+	eg, ctx := NewErrorGroupWithContext(context.Background())
+
+	numToAdd := 100
+	for i := 0; i < numToAdd; i++ {
+		i := i
+		eg.Go(func() error {
+			if i%2 == 0 {
+				return nil
+			}
+
+			return errors.New(generateRandomString(10))
+		})
+	}
+
+	err := eg.Wait()
+
+	t.Run("verify Wait() returns the errors of every task", func(t *testing.T) {
+		assert.Equal(t, numToAdd/2, eg.Len())
+		assert.Equal(t, eg.Error(), err.Error())
+	})
+	t.Run("verify Wait() cancels the context", func(t *testing.T) {
+		assert.Equal(t, context.Canceled, ctx.Err())
+	})
+	t.Run("verify Go() does not start tasks once the group is closed", func(t *testing.T) {
+		eg.Close()
+		assert.False(t, eg.Go(func() error { return nil }))
+	})
+}
+
+func TestErrorStatusGroup_Go(t *testing.T) {
+	esg := NewErrorStatusGroup()
+
+	esg.Go(func() (int, error) {
+		return 200, nil
+	})
+	esg.Go(func() (int, error) {
+		return 503, errors.New("unavailable")
+	})
+
+	status, err := esg.Wait()
+
+	t.Run("verify Wait() returns the highest status and the combined error", func(t *testing.T) {
+		assert.Equal(t, 503, status)
+		assert.Equal(t, "lowest status: [200]\nhighest status: [503]\nstatus counts: [200: 1, 503: 1]\nstatus classes: [2xx: 1, 5xx: 1]\nunavailable", err.Error())
+	})
+	t.Run("verify Go() records the status of every task", func(t *testing.T) {
+		assert.Equal(t, 2, esg.LenStatuses())
+		assert.Equal(t, 1, esg.LenErrors())
+	})
+}
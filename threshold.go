@@ -0,0 +1,82 @@
+package error_group
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ThresholdExceededError is returned by ToError, ToStatusAndError and Wait once a group created
+// with MaxErrors, MaxErrorRate or StatusThreshold has crossed one of its thresholds. It wraps a
+// snapshot of the errors collected by the group, or nil if the group holds no errors.
+type ThresholdExceededError struct {
+	Err    error
+	Reason string
+}
+
+// Error fulfills the builtin.Error interface.
+func (tee *ThresholdExceededError) Error() string {
+	if tee.Err == nil {
+		return "threshold exceeded: " + tee.Reason
+	}
+
+	return "threshold exceeded: " + tee.Reason + "\n" + tee.Err.Error()
+}
+
+// Unwrap returns the errors collected by the group.
+func (tee *ThresholdExceededError) Unwrap() error {
+	return tee.Err
+}
+
+// MaxErrors trips a group once it holds more than n errors. MaxErrors(0) makes a group fail fast
+// on the first error.
+func MaxErrors(n int) Option {
+	return func(cfg *config) {
+		cfg.hasMaxErrors = true
+		cfg.maxErrors = n
+	}
+}
+
+// MaxErrorRate trips a group once at least minSamples outcomes have been recorded and more than
+// fraction of them are errors. For an error status group every entry is an outcome. For an error
+// group every error and every task started with Go that returned nil is an outcome.
+func MaxErrorRate(fraction float64, minSamples int) Option {
+	return func(cfg *config) {
+		cfg.hasMaxErrorRate = true
+		cfg.maxErrorRate = fraction
+		cfg.minSamples = minSamples
+	}
+}
+
+// StatusThreshold trips an error status group once a status value greater than or equal to status
+// is added to it. It has no effect on an error group.
+func StatusThreshold(status int) Option {
+	return func(cfg *config) {
+		cfg.statusThreshold = status
+	}
+}
+
+// errorsExceeded returns the reason a group holding errorCount errors out of samples outcomes
+// crosses the configured thresholds, or the empty string if it does not.
+func (cfg config) errorsExceeded(errorCount, samples int) string {
+	if cfg.hasMaxErrors && errorCount > cfg.maxErrors {
+		return "more than " + strconv.Itoa(cfg.maxErrors) + " errors"
+	}
+
+	if cfg.hasMaxErrorRate && samples > 0 && samples >= cfg.minSamples {
+		if rate := float64(errorCount) / float64(samples); rate > cfg.maxErrorRate {
+			return fmt.Sprintf("error rate %.2f above %.2f", rate, cfg.maxErrorRate)
+		}
+	}
+
+	return ""
+}
+
+// statusExceeded returns the reason status crosses the configured status threshold, or the empty
+// string if it does not.
+func (cfg config) statusExceeded(status int) string {
+	if cfg.statusThreshold > 0 && status >= cfg.statusThreshold {
+		return "status [" + strconv.Itoa(status) + "] at or above [" + strconv.Itoa(cfg.statusThreshold) + "]"
+	}
+
+	return ""
+}
@@ -0,0 +1,103 @@
+package error_group
+
+import (
+	"context"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"testing"
+)
+
+func TestMaxErrors(t *testing.T) {
+	eg, ctx := NewErrorGroupWithContext(context.Background(), MaxErrors(2))
+
+	eg.Add(errors.New("first message"))
+	eg.Add(errors.New("second message"))
+
+	t.Run("verify the group does not trip while it holds at most n errors", func(t *testing.T) {
+		assert.False(t, eg.Tripped())
+		assert.Nil(t, ctx.Err())
+		assert.Equal(t, "first message\nsecond message", eg.ToError().Error())
+	})
+
+	eg.Add(errors.New("third message"))
+
+	t.Run("verify the group trips once it holds more than n errors", func(t *testing.T) {
+		assert.True(t, eg.Tripped())
+	})
+	t.Run("verify tripping cancels the context with a ThresholdExceededError cause", func(t *testing.T) {
+		assert.Equal(t, context.Canceled, ctx.Err())
+
+		var cause *ThresholdExceededError
+		assert.True(t, errors.As(context.Cause(ctx), &cause))
+		assert.Equal(t, "more than 2 errors", cause.Reason)
+	})
+	t.Run("verify Go() rejects new tasks once the group tripped", func(t *testing.T) {
+		assert.False(t, eg.Go(func() error { return nil }))
+	})
+	t.Run("verify ToError() returns a ThresholdExceededError wrapping the collected errors", func(t *testing.T) {
+		err := eg.ToError()
+
+		var exceeded *ThresholdExceededError
+		assert.True(t, errors.As(err, &exceeded))
+		assert.Equal(t, "threshold exceeded: more than 2 errors\nfirst message\nsecond message\nthird message", err.Error())
+		assert.Equal(t, eg.Error(), exceeded.Err.Error())
+	})
+	t.Run("verify Reset() clears the tripped state", func(t *testing.T) {
+		eg.Reset()
+		assert.False(t, eg.Tripped())
+		assert.Nil(t, eg.ToError())
+	})
+}
+
+func TestMaxErrorRate(t *testing.T) {
+	eg := NewErrorGroup(MaxErrorRate(0.5, 4))
+
+	eg.Go(func() error { return errors.New("first message") })
+	eg.Go(func() error { return errors.New("second message") })
+	eg.Go(func() error { return errors.New("third message") })
+	eg.Wait()
+
+	t.Run("verify the group does not trip before minSamples outcomes", func(t *testing.T) {
+		assert.False(t, eg.Tripped())
+	})
+
+	eg.Go(func() error { return nil })
+	eg.Wait()
+
+	t.Run("verify the group trips once the error rate is above the fraction", func(t *testing.T) {
+		assert.True(t, eg.Tripped())
+
+		var exceeded *ThresholdExceededError
+		assert.True(t, errors.As(eg.Wait(), &exceeded))
+		assert.Equal(t, "error rate 0.75 above 0.50", exceeded.Reason)
+	})
+}
+
+func TestStatusThreshold(t *testing.T) {
+	esg := NewErrorStatusGroup(StatusThreshold(500))
+
+	esg.AddStatus(404)
+
+	t.Run("verify the group does not trip below the status threshold", func(t *testing.T) {
+		assert.False(t, esg.Tripped())
+		assert.Nil(t, esg.ToError())
+	})
+
+	esg.AddStatus(503)
+
+	t.Run("verify the group trips at the status threshold even without errors", func(t *testing.T) {
+		assert.True(t, esg.Tripped())
+
+		status, err := esg.ToStatusAndError()
+		assert.Equal(t, 503, status)
+		assert.Equal(t, "threshold exceeded: status [503] at or above [500]", err.Error())
+	})
+	t.Run("verify the snapshot records the tripped state", func(t *testing.T) {
+		assert.True(t, esg.Snapshot().Tripped())
+	})
+	t.Run("verify StatusThreshold() has no effect on an error group", func(t *testing.T) {
+		eg := NewErrorGroup(StatusThreshold(500))
+		eg.Add(errors.New("first message"))
+		assert.False(t, eg.Tripped())
+	})
+}
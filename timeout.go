@@ -0,0 +1,166 @@
+package error_group
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// TimeoutError is recorded when a function started with GoWithTimeout or GoWithDeadline does not
+// return before its deadline. It matches context.DeadlineExceeded with errors.Is.
+type TimeoutError struct {
+	Task    string
+	Elapsed time.Duration
+	Timeout time.Duration
+}
+
+// Error fulfills the builtin.Error interface.
+func (te *TimeoutError) Error() string {
+	return fmt.Sprintf("task %q timed out after %s (timeout %s)", te.Task, te.Elapsed, te.Timeout)
+}
+
+// Is reports whether target is context.DeadlineExceeded.
+func (te *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// StatusCode returns the status value an error status group records for a TimeoutError.
+func (te *TimeoutError) StatusCode() int {
+	return http.StatusGatewayTimeout
+}
+
+// GoWithDeadline calls f in a new goroutine with a context that is cancelled at deadline and adds
+// the error it returns to this error group instance. If f has not returned by the deadline a
+// *TimeoutError naming the task is added right away and the value f eventually returns is
+// discarded, so Wait does not wait for it. The deadline is measured with the group's Clock.
+func (eg *ErrorGroup) GoWithDeadline(name string, deadline time.Time, f func(context.Context) error) bool {
+	clock := eg.config.clockOrDefault()
+
+	return eg.launch(func() Entry {
+		return runWithDeadline(eg.ctx, clock, name, clock.Now(), deadline, func(ctx context.Context) Entry {
+			return Entry{Err: f(ctx)}
+		})
+	})
+}
+
+// GoWithTimeout is like GoWithDeadline but every attempt of f is given timeout to return.
+func (eg *ErrorGroup) GoWithTimeout(name string, timeout time.Duration, f func(context.Context) error) bool {
+	clock := eg.config.clockOrDefault()
+
+	return eg.launch(func() Entry {
+		start := clock.Now()
+
+		return runWithDeadline(eg.ctx, clock, name, start, start.Add(timeout), func(ctx context.Context) Entry {
+			return Entry{Err: f(ctx)}
+		})
+	})
+}
+
+// GoWithDeadline calls f in a new goroutine with a context that is cancelled at deadline and adds
+// the status and error it returns to this error status group instance. If f has not returned by
+// the deadline a *TimeoutError naming the task is added right away with a status of 504 and the
+// values f eventually returns are discarded, so Wait does not wait for them. The deadline is
+// measured with the group's Clock.
+func (esg *ErrorStatusGroup) GoWithDeadline(name string, deadline time.Time, f func(context.Context) (int, error)) bool {
+	clock := esg.config.clockOrDefault()
+
+	return esg.launch(false, func() Entry {
+		return runWithDeadline(esg.ctx, clock, name, clock.Now(), deadline, func(ctx context.Context) Entry {
+			status, err := f(ctx)
+
+			return Entry{Err: err, HasStatus: true, Status: status}
+		})
+	})
+}
+
+// GoWithTimeout is like GoWithDeadline but every attempt of f is given timeout to return.
+func (esg *ErrorStatusGroup) GoWithTimeout(name string, timeout time.Duration, f func(context.Context) (int, error)) bool {
+	clock := esg.config.clockOrDefault()
+
+	return esg.launch(false, func() Entry {
+		start := clock.Now()
+
+		return runWithDeadline(esg.ctx, clock, name, start, start.Add(timeout), func(ctx context.Context) Entry {
+			status, err := f(ctx)
+
+			return Entry{Err: err, HasStatus: true, Status: status}
+		})
+	})
+}
+
+// runWithDeadline calls f with a context derived from parent that is cancelled once clock reaches
+// deadline. It returns the entry f produced or, when deadline passes first, an entry holding a
+// *TimeoutError whose elapsed time is measured from start. f keeps running in the background after
+// a timeout; its result is dropped. When parent itself is cancelled f is expected to notice and
+// return, and its result is used as is, as long as it returns before deadline.
+func runWithDeadline(parent context.Context, clock Clock, name string, start, deadline time.Time, f func(context.Context) Entry) Entry {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	inner, cancel := context.WithCancelCause(parent)
+	defer cancel(nil)
+
+	ctx := &deadlineContext{Context: inner, deadline: deadline}
+
+	done := make(chan Entry, 1)
+	go func() {
+		done <- callProtected(func() Entry {
+			return f(ctx)
+		})
+	}()
+
+	timer := clock.After(deadline.Sub(start))
+
+	select {
+	case entry := <-done:
+		return entry
+	case <-inner.Done():
+		// parent was cancelled: f is expected to notice and return, but only until deadline.
+		select {
+		case entry := <-done:
+			return entry
+		case <-timer:
+		}
+	case <-timer:
+		ctx.expired.Store(true)
+		cancel(context.DeadlineExceeded)
+	}
+
+	timeoutError := &TimeoutError{
+		Task:    name,
+		Elapsed: clock.Now().Sub(start),
+		Timeout: deadline.Sub(start),
+	}
+
+	return Entry{Err: timeoutError, HasStatus: true, Status: timeoutError.StatusCode()}
+}
+
+// deadlineContext is the context runWithDeadline calls f with. It reports deadline and, once the
+// clock of the group has reached it, context.DeadlineExceeded, the same way a context created with
+// context.WithDeadline would.
+type deadlineContext struct {
+	context.Context
+	deadline time.Time
+	expired  atomic.Bool
+}
+
+// Deadline fulfills the context.Context interface.
+func (dc *deadlineContext) Deadline() (time.Time, bool) {
+	if parentDeadline, ok := dc.Context.Deadline(); ok && parentDeadline.Before(dc.deadline) {
+		return parentDeadline, true
+	}
+
+	return dc.deadline, true
+}
+
+// Err fulfills the context.Context interface.
+func (dc *deadlineContext) Err() error {
+	if dc.expired.Load() {
+		return context.DeadlineExceeded
+	}
+
+	return dc.Context.Err()
+}
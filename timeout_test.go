@@ -0,0 +1,202 @@
+package error_group
+
+import (
+	"context"
+	"errors"
+	"github.com/jgroeneveld/trial/assert"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock whose timers only fire when Advance moves its time past them. Every call
+// to After is signalled on armed so that tests can wait for a task to start waiting.
+type manualClock struct {
+	armed  chan struct{}
+	mutex  sync.Mutex
+	now    time.Time
+	timers []manualTimer
+}
+
+// manualTimer is a timer created by manualClock.After.
+type manualTimer struct {
+	at    time.Time
+	fired chan time.Time
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{armed: make(chan struct{}, 100)}
+}
+
+func (mc *manualClock) After(d time.Duration) <-chan time.Time {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	fired := make(chan time.Time, 1)
+	mc.timers = append(mc.timers, manualTimer{at: mc.now.Add(d), fired: fired})
+	mc.armed <- struct{}{}
+
+	return fired
+}
+
+func (mc *manualClock) Now() time.Time {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	return mc.now
+}
+
+// Advance moves the time of this clock forward by d once n timers have been armed, and fires every
+// timer that is due.
+func (mc *manualClock) Advance(n int, d time.Duration) {
+	for range n {
+		<-mc.armed
+	}
+
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	mc.now = mc.now.Add(d)
+
+	pending := mc.timers[:0]
+	for _, timer := range mc.timers {
+		if timer.at.After(mc.now) {
+			pending = append(pending, timer)
+		} else {
+			timer.fired <- mc.now
+		}
+	}
+
+	mc.timers = pending
+}
+
+func TestErrorGroup_GoWithTimeout(t *testing.T) {
+	clock := newManualClock()
+	eg := NewErrorGroup(UseClock(clock))
+	release := make(chan struct{})
+	defer close(release)
+
+	eg.GoWithTimeout("fast", time.Second, func(ctx context.Context) error {
+		return nil
+	})
+	eg.GoWithTimeout("stuck", 10*time.Millisecond, func(ctx context.Context) error {
+		<-release
+		return errors.New("too late")
+	})
+
+	clock.Advance(2, 10*time.Millisecond)
+	err := eg.Wait()
+
+	t.Run("verify a task exceeding its timeout records a TimeoutError without being waited for", func(t *testing.T) {
+		assert.Equal(t, 1, eg.Len())
+
+		timeouts := AsAll[*TimeoutError](eg)
+		assert.Equal(t, 1, len(timeouts))
+		assert.Equal(t, "stuck", timeouts[0].Task)
+		assert.Equal(t, 10*time.Millisecond, timeouts[0].Timeout)
+		assert.Equal(t, 10*time.Millisecond, timeouts[0].Elapsed)
+		assert.NotNil(t, err)
+	})
+	t.Run("verify a TimeoutError matches context.DeadlineExceeded", func(t *testing.T) {
+		assert.NotNil(t, eg.Find(context.DeadlineExceeded))
+	})
+}
+
+func TestErrorGroup_GoWithDeadline(t *testing.T) {
+	clock := newManualClock()
+	eg := NewErrorGroup(UseClock(clock))
+	deadline := clock.Now().Add(10 * time.Millisecond)
+
+	observed := make(chan context.Context, 1)
+	eg.GoWithDeadline("respects ctx", deadline, func(ctx context.Context) error {
+		<-ctx.Done()
+		observed <- ctx
+		return ctx.Err()
+	})
+
+	clock.Advance(1, 10*time.Millisecond)
+	eg.Wait()
+	ctx := <-observed
+
+	t.Run("verify a task returning its context error at the deadline records a TimeoutError", func(t *testing.T) {
+		assert.Equal(t, 1, len(AsAll[*TimeoutError](eg)))
+	})
+	t.Run("verify the context reports the deadline of the group's clock", func(t *testing.T) {
+		ctxDeadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, deadline, ctxDeadline)
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	})
+}
+
+func TestErrorStatusGroup_GoWithTimeout(t *testing.T) {
+	clock := newManualClock()
+	esg := NewErrorStatusGroup(UseClock(clock))
+	release := make(chan struct{})
+	defer close(release)
+
+	esg.GoWithTimeout("teacher.Search", time.Second, func(ctx context.Context) (int, error) {
+		return http.StatusOK, nil
+	})
+	esg.GoWithTimeout("learner.Search", 10*time.Millisecond, func(ctx context.Context) (int, error) {
+		<-release
+		return http.StatusOK, nil
+	})
+
+	clock.Advance(2, 10*time.Millisecond)
+	status, err := esg.Wait()
+
+	t.Run("verify a timed out task is recorded with a 504 status", func(t *testing.T) {
+		assert.Equal(t, http.StatusGatewayTimeout, status)
+		assert.Equal(t, 2, esg.LenStatuses())
+		assert.Equal(t, 1, esg.LenErrors())
+		assert.NotNil(t, err)
+	})
+	t.Run("verify the TimeoutError names the task", func(t *testing.T) {
+		timeouts := AsAll[*TimeoutError](esg)
+		assert.Equal(t, 1, len(timeouts))
+		assert.Equal(t, "learner.Search", timeouts[0].Task)
+	})
+}
+
+func TestErrorStatusGroup_GoWithTimeout_ParentCancelled(t *testing.T) {
+	esg, _ := NewErrorStatusGroupWithContext(context.Background(), MaxErrors(0))
+	clock := newManualClock()
+
+	esg.AddError(errors.New("trip the group"))
+
+	entry := runWithDeadline(esg.ctx, clock, "cancelled", clock.Now(), clock.Now().Add(time.Hour), func(ctx context.Context) Entry {
+		<-ctx.Done()
+		return Entry{Err: ctx.Err(), HasStatus: true, Status: 499}
+	})
+
+	t.Run("verify cancellation of the parent context is not reported as a timeout", func(t *testing.T) {
+		assert.Equal(t, context.Canceled, entry.Err)
+		assert.Equal(t, 499, entry.Status)
+	})
+}
+
+func TestErrorStatusGroup_GoWithTimeout_ParentCancelledIgnored(t *testing.T) {
+	clock := newManualClock()
+	esg, _ := NewErrorStatusGroupWithContext(context.Background(), UseClock(clock), MaxErrors(0))
+	release := make(chan struct{})
+	defer close(release)
+
+	esg.GoWithTimeout("ignores ctx", 50*time.Millisecond, func(ctx context.Context) (int, error) {
+		<-release
+		return http.StatusOK, nil
+	})
+
+	esg.AddError(errors.New("trip the group"))
+	clock.Advance(1, 50*time.Millisecond)
+	status, _ := esg.Wait()
+
+	t.Run("verify the timeout still applies once the parent context is cancelled", func(t *testing.T) {
+		assert.Equal(t, http.StatusGatewayTimeout, status)
+
+		timeouts := AsAll[*TimeoutError](esg)
+		assert.Equal(t, 1, len(timeouts))
+		assert.Equal(t, "ignores ctx", timeouts[0].Task)
+	})
+}